@@ -1,3 +1,5 @@
 module github.com/nurtai325/qurtc
 
 go 1.24.4
+
+require golang.org/x/text v0.34.0
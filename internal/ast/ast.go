@@ -4,11 +4,28 @@ import (
 	"github.com/nurtai325/qurtc/internal/token"
 )
 
+// Node is implemented by every declaration, statement and expression.
+type Node interface {
+	Pos() token.Pos // position of the first character of the node
+	End() token.Pos // position right after the last character of the node
+}
+
+// Span is the source range of a node, filled in by the parser.
+type Span struct {
+	StartPos token.Pos
+	EndPos   token.Pos
+}
+
+func (s *Span) Pos() token.Pos { return s.StartPos }
+
+func (s *Span) End() token.Pos { return s.EndPos }
+
 // Declarations
 // ----------------------------------------------------------------------------
 
 type (
 	Decl interface {
+		Node
 		aDecl()
 	}
 
@@ -26,15 +43,22 @@ type (
 		decl
 	}
 
+	// top-level айнымалы or тұрақты
+	VarDecl struct {
+		Var *VarStmt
+		decl
+	}
+
 	// only for builtin funcs
 	BuiltinFuncDecl struct {
 		Name *NameExpr
-		Body func(args ...any) error
+		Body func(args ...any) (any, error) // the result is nil for no value
 		decl
 	}
 )
 
 type decl struct {
+	Span
 }
 
 func (*decl) aDecl() {}
@@ -49,11 +73,19 @@ type Field struct {
 	Type *Type
 }
 
+// CaseClause is one жағдай of a таңда, Values is nil for әдепкі.
+type CaseClause struct {
+	Values []Expr
+	Body   []Stmt
+	Span
+}
+
 // Expressions
 // ----------------------------------------------------------------------------
 
 type (
 	Expr interface {
+		Node
 		aExpr()
 	}
 
@@ -67,6 +99,13 @@ type (
 		expr
 	}
 
+	// "Аты: {адам.аты}" is the parts "Аты: " and адам.аты, the text parts
+	// are *StringExpr
+	InterpExpr struct {
+		Parts []Expr
+		expr
+	}
+
 	IntExpr struct {
 		Value int
 		expr
@@ -87,6 +126,13 @@ type (
 		expr
 	}
 
+	// {"а": 1, "б": 2}, Keys[i] is the key of Values[i]
+	MapExpr struct {
+		Keys   []Expr
+		Values []Expr
+		expr
+	}
+
 	CallExpr struct {
 		Func Expr
 		Args []Expr // if nil then no args
@@ -99,6 +145,7 @@ type (
 		expr
 	}
 
+	// an element of an array or a list, or the value of a key of a map
 	ArrayAccessExpr struct {
 		Array Expr
 		Index Expr
@@ -116,9 +163,22 @@ type (
 		Left, Right Expr
 		expr
 	}
+
+	// (а + б), kept to print the program as it is written
+	ParenExpr struct {
+		X Expr
+		expr
+	}
+
+	// 0..10 in a for-each, the integers from From up to but not including To
+	RangeExpr struct {
+		From, To Expr
+		expr
+	}
 )
 
 type expr struct {
+	Span
 }
 
 func (*expr) aExpr() {}
@@ -128,22 +188,32 @@ func (*expr) aExpr() {}
 
 type (
 	Stmt interface {
+		Node
 		aStmt()
 	}
 
 	VarStmt struct {
-		Name *NameExpr
-		Type *Type
-		Val  Expr // zero value if nil
+		Name  *NameExpr
+		Type  *Type
+		Val   Expr // zero value if nil
+		Const bool // declared with тұрақты, Val is never nil then
 		stmt
 	}
 
 	AssignStmt struct {
-		Var Expr // *ArrayAccess, *Selector, *NameExpr
+		Var Expr        // *ArrayAccess, *Selector, *NameExpr
+		Op  token.Token // the operator of +=, -= and so on, 0 for =
 		Val Expr
 		stmt
 	}
 
+	// а++ or а--
+	IncDecStmt struct {
+		Var Expr
+		Op  token.Token // token.INC or token.DEC
+		stmt
+	}
+
 	CallStmt struct {
 		CallExpr *CallExpr
 		stmt
@@ -156,6 +226,8 @@ type (
 		stmt
 	}
 
+	// Init and Post are nil in a while loop қайтала (шарт), Cond is nil too
+	// in an infinite loop қайтала { }
 	ForStmt struct {
 		Init *VarStmt
 		Cond Expr
@@ -164,21 +236,50 @@ type (
 		stmt
 	}
 
+	// қайтала (і, элемент : тізім), Key is the index of an array, a list or
+	// a string, or the key of a map, nil if left out. With one name a map
+	// gives its keys. X is a *RangeExpr in қайтала (і : 0..10).
+	ForEachStmt struct {
+		Key   *NameExpr
+		Value *NameExpr
+		X     Expr
+		Body  []Stmt
+		stmt
+	}
+
+	// таңда (Tag) { жағдай 1, 2: ... әдепкі: ... }, only the first case
+	// with a value equal to Tag is run
+	SwitchStmt struct {
+		Tag   Expr
+		Cases []*CaseClause
+		stmt
+	}
+
 	ReturnStmt struct {
-		Value Expr
+		Value Expr // nil for a bare қайтар
+		stmt
+	}
+
+	// сыртқы: қайтала (...) { }, Stmt is a *ForStmt or a *ForEachStmt
+	LabeledStmt struct {
+		Label *NameExpr
+		Stmt  Stmt
 		stmt
 	}
 
 	BreakStmt struct {
+		Label *NameExpr // the loop тоқта сыртқы ends, nil for the innermost
 		stmt
 	}
 
 	ContinueStmt struct {
+		Label *NameExpr // the loop өткіз сыртқы goes on with, nil for the innermost
 		stmt
 	}
 )
 
 type stmt struct {
+	Span
 }
 
 func (*stmt) aStmt() {}
@@ -187,6 +288,51 @@ type Stmts []Stmt
 
 func (Stmts) aStmt() {}
 
+func (s Stmts) Pos() token.Pos {
+	if len(s) == 0 {
+		return token.Pos{}
+	}
+	return s[0].Pos()
+}
+
+func (s Stmts) End() token.Pos {
+	if len(s) == 0 {
+		return token.Pos{}
+	}
+	return s[len(s)-1].End()
+}
+
+// Unparen returns expr without the parentheses around it.
+func Unparen(expr Expr) Expr {
+	for {
+		paren, ok := expr.(*ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// AssignedVar returns the variable changed by assigning to expr: the
+// variable itself or the array or struct variable an element or a field of
+// which is assigned. It returns nil if expr is not based on a variable.
+func AssignedVar(expr Expr) *NameExpr {
+	for {
+		switch v := expr.(type) {
+		case *NameExpr:
+			return v
+		case *ArrayAccessExpr:
+			expr = v.Array
+		case *SelectorExpr:
+			expr = v.Struct
+		case *ParenExpr:
+			expr = v.X
+		default:
+			return nil
+		}
+	}
+}
+
 // Types
 // ----------------------------------------------------------------------------
 
@@ -194,13 +340,22 @@ type Type struct {
 	Kind     Kind
 	Name     *NameExpr
 	IsArray  bool
-	ArrayLen int
+	ArrayLen int // 0 for a list, an array that can grow
+	// the types of the keys and the values of a map, like сөздік[жол]бүтін
+	Key, Value *Type
+	Span
+}
+
+// IsList reports whether t is a list like []бүтін.
+func (t *Type) IsList() bool {
+	return t.IsArray && t.ArrayLen == 0
 }
 
 type Kind int
 
-func GetKind(typeName string) Kind {
-	for i, kindName := range primitiveTypes {
+// GetKind returns the kind of the type called typeName in dialect d.
+func GetKind(typeName string, d token.Dialect) Kind {
+	for i, kindName := range primitiveTypes[d] {
 		if typeName == kindName {
 			return Kind(i)
 		}
@@ -209,10 +364,15 @@ func GetKind(typeName string) Kind {
 }
 
 func (k Kind) String() string {
-	if int(k) >= len(primitiveTypes) {
-		return token.STRUCT.String()
+	return k.In(token.Cyrillic)
+}
+
+// In returns the name of a primitive kind in dialect d.
+func (k Kind) In(d token.Dialect) string {
+	if k >= TStruct {
+		return token.STRUCT.In(d)
 	}
-	return primitiveTypes[k]
+	return primitiveTypes[d][k]
 }
 
 const (
@@ -221,13 +381,33 @@ const (
 	TFloat
 	TString
 	TBool
+	TMap
 	TStruct
 )
 
-var primitiveTypes = [...]string{
-	TVoid:   "ештеңе",
-	TInt:    "бүтін",
-	TFloat:  "бөлшек",
-	TString: "жол",
-	TBool:   "шын",
+var primitiveTypes = [...][TStruct]string{
+	token.Cyrillic: {
+		TVoid:   "ештеңе",
+		TInt:    "бүтін",
+		TFloat:  "бөлшек",
+		TString: "жол",
+		TBool:   "шын",
+		TMap:    "сөздік",
+	},
+	token.Latin: {
+		TVoid:   "eşteñe",
+		TInt:    "bütın",
+		TFloat:  "bölşek",
+		TString: "jol",
+		TBool:   "şyn",
+		TMap:    "sözdık",
+	},
+	token.Arabic: {
+		TVoid:   "ەشتەڭە",
+		TInt:    "ٴبۇتىن",
+		TFloat:  "بولشەك",
+		TString: "جول",
+		TBool:   "شىن",
+		TMap:    "سوزدىك",
+	},
 }
@@ -0,0 +1,8 @@
+package ast
+
+import "errors"
+
+var (
+	ErrInvalidJSON  = errors.New("JSON түрінде жазылған бағдарлама қате")
+	ErrNotEncodable = errors.New("бұл түйінді JSON түрінде жазу мүмкін емес")
+)
@@ -0,0 +1,445 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+// A program is written as JSON as the array of its declarations. Every
+// node is an object with its type in "node", its fields named like the Go
+// fields starting with a lowercase letter and its range in "pos" and "end":
+//
+//	{"node": "IntExpr", "value": 1, "pos": {"line": 1, "col": 5, "offset": 4}, "end": ...}
+//
+// Operators are written as in the source, kinds of types by their Cyrillic
+// names. Positions may be left out in a generated program.
+
+// nodeTypes are the structs that can be in a JSON program.
+var nodeTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []any{
+		StructDecl{}, FuncDecl{}, VarDecl{}, FuncArg{}, Field{}, CaseClause{},
+		NameExpr{}, StringExpr{}, InterpExpr{}, IntExpr{}, FloatExpr{}, BoolExpr{}, ArrayExpr{}, MapExpr{},
+		CallExpr{}, SelectorExpr{}, ArrayAccessExpr{}, UnaryOpExpr{}, OpExpr{}, ParenExpr{}, RangeExpr{},
+		VarStmt{}, AssignStmt{}, IncDecStmt{}, CallStmt{}, IfStmt{}, ForStmt{}, ForEachStmt{}, SwitchStmt{}, LabeledStmt{}, ReturnStmt{}, BreakStmt{}, ContinueStmt{},
+		Type{},
+	} {
+		t := reflect.TypeOf(node)
+		nodeTypes[t.Name()] = t
+	}
+}
+
+var (
+	posType   = reflect.TypeFor[token.Pos]()
+	kindType  = reflect.TypeFor[Kind]()
+	tokenType = reflect.TypeFor[token.Token]()
+	stmtsType = reflect.TypeFor[Stmts]()
+)
+
+// EncodeJSON writes decls to w as indented JSON.
+func EncodeJSON(w io.Writer, decls []Decl) error {
+	v, err := encode(reflect.ValueOf(decls))
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
+
+// member is a key and a value of a JSON object. Objects are slices of
+// members to keep the fields in the order of the struct, so that the same
+// program is always written the same way.
+type member struct {
+	key   string
+	value any
+}
+
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		b.Write(key)
+		b.WriteByte(':')
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func (o object) get(key string) any {
+	for _, m := range o {
+		if m.key == key {
+			return m.value
+		}
+	}
+	return nil
+}
+
+// encode converts v to an object, a []any or a JSON scalar.
+func encode(v reflect.Value) (any, error) {
+	switch v.Type() {
+	case posType:
+		pos := v.Interface().(token.Pos)
+		if !pos.IsValid() {
+			return nil, nil
+		}
+		return object{{"line", pos.Line}, {"col", pos.Col}, {"offset", pos.Offset}}, nil
+	case kindType:
+		return v.Interface().(Kind).String(), nil
+	case tokenType:
+		tok := v.Interface().(token.Token)
+		if tok == token.ILLEGAL {
+			// no operator, like in a plain assignment
+			return nil, nil
+		}
+		return tok.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Elem().Type() == stmtsType {
+			// a list of statements in place of one, like an else block
+			list, err := encode(v.Elem())
+			return object{{"node", "Stmts"}, {"list", list}}, err
+		}
+		return encode(v.Elem())
+	case reflect.Slice:
+		list := make([]any, v.Len())
+		for i := range list {
+			var err error
+			if list[i], err = encode(v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case reflect.Struct:
+		o := object{{"node", v.Type().Name()}}
+		return encodeFields(o, v)
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int:
+		return v.Int(), nil
+	case reflect.Float32:
+		return v.Float(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotEncodable, v.Type())
+}
+
+// encodeFields adds the fields of the struct v to o, the fields of
+// embedded structs like Span are added as its own.
+func encodeFields(o object, v reflect.Value) (object, error) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			var err error
+			if o, err = encodeFields(o, v.Field(i)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := encode(v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, member{jsonName(field.Name), value})
+	}
+	return o, nil
+}
+
+func jsonName(field string) string {
+	switch field {
+	case "StartPos":
+		return "pos"
+	case "EndPos":
+		return "end"
+	}
+	return strings.ToLower(field[:1]) + field[1:]
+}
+
+// DecodeJSON reads the declarations of a program written as JSON, the
+// positions in them are in filename.
+func DecodeJSON(filename string, data []byte) ([]Decl, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+	d := decoder{filename: filename}
+	var decls []Decl
+	out := reflect.ValueOf(&decls).Elem()
+	if err := d.decode(v, out, "бағдарлама"); err != nil {
+		return nil, err
+	}
+	return decls, nil
+}
+
+type decoder struct {
+	filename string
+}
+
+func (d *decoder) errorf(path, format string, args ...any) error {
+	return d.errorAt(token.Pos{File: d.filename}, path, format, args...)
+}
+
+// errorAt is like errorf but for a decoded node with a known position.
+func (d *decoder) errorAt(pos token.Pos, path, format string, args ...any) error {
+	return fmt.Errorf("%w: %s: %s: %s", ErrInvalidJSON, pos, path, fmt.Sprintf(format, args...))
+}
+
+// decode sets out to the JSON value v found at path.
+func (d *decoder) decode(v any, out reflect.Value, path string) error {
+	if v == nil {
+		out.SetZero()
+		return nil
+	}
+	switch out.Type() {
+	case posType:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return d.errorf(path, "орын объект болуы керек")
+		}
+		pos := token.Pos{File: d.filename}
+		for key, field := range map[string]*int{"line": &pos.Line, "col": &pos.Col, "offset": &pos.Offset} {
+			if err := d.decode(m[key], reflect.ValueOf(field).Elem(), path+"."+key); err != nil {
+				return err
+			}
+		}
+		out.Set(reflect.ValueOf(pos))
+		return nil
+	case kindType:
+		name, ok := v.(string)
+		kind := GetKind(name, token.Cyrillic)
+		if !ok || kind == TStruct && name != TStruct.String() {
+			return d.errorf(path, "бұндай тип түрі жоқ: %v", v)
+		}
+		out.Set(reflect.ValueOf(kind))
+		return nil
+	case tokenType:
+		op, ok := v.(string)
+		tok, found := token.LookupOperator(op)
+		if !ok || !found {
+			return d.errorf(path, "бұндай оператор жоқ: %v", v)
+		}
+		out.Set(reflect.ValueOf(tok))
+		return nil
+	}
+
+	switch out.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return d.errorf(path, "объект күтілген")
+		}
+		name, _ := m["node"].(string)
+		var node reflect.Value
+		if name == "Stmts" {
+			node = reflect.New(stmtsType).Elem()
+			if err := d.decode(m["list"], node, path+".list"); err != nil {
+				return err
+			}
+		} else {
+			t, ok := nodeTypes[name]
+			if !ok {
+				return d.errorf(path, "бұндай түйін жоқ: %q", name)
+			}
+			node = reflect.New(t)
+			if err := d.decodeStruct(m, node.Elem(), path); err != nil {
+				return err
+			}
+			if err := d.check(node, path); err != nil {
+				return err
+			}
+		}
+		if !node.Type().AssignableTo(out.Type()) {
+			return d.errorf(path, "бұл жерде %s болуы мүмкін емес", name)
+		}
+		out.Set(node)
+	case reflect.Slice:
+		list, ok := v.([]any)
+		if !ok {
+			return d.errorf(path, "тізім күтілген")
+		}
+		if len(list) == 0 {
+			out.SetZero()
+			return nil
+		}
+		out.Set(reflect.MakeSlice(out.Type(), len(list), len(list)))
+		for i, elem := range list {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if err := d.decode(elem, out.Index(i), elemPath); err != nil {
+				return err
+			}
+			if k := out.Index(i).Kind(); (k == reflect.Pointer || k == reflect.Interface) && out.Index(i).IsNil() {
+				return d.errorf(elemPath, "тізімде null болмауы керек")
+			}
+		}
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return d.errorf(path, "жол күтілген")
+		}
+		out.SetString(s)
+	case reflect.Int:
+		n, ok := v.(json.Number)
+		i, err := n.Int64()
+		if !ok || err != nil {
+			return d.errorf(path, "бүтін сан күтілген")
+		}
+		out.SetInt(i)
+	case reflect.Float32:
+		n, ok := v.(json.Number)
+		f, err := n.Float64()
+		if !ok || err != nil {
+			return d.errorf(path, "сан күтілген")
+		}
+		out.SetFloat(f)
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return d.errorf(path, "иә немесе жоқ күтілген")
+		}
+		out.SetBool(b)
+	default:
+		return d.errorf(path, "%s JSON арқылы берілмейді", out.Type())
+	}
+	return nil
+}
+
+// optional are the fields of nodes that can be left out, the other node
+// fields must be given.
+var optional = map[string]bool{
+	"VarStmt.Val":        true,
+	"IfStmt.Else":        true,
+	"ForStmt.Init":       true,
+	"ForStmt.Cond":       true,
+	"ForStmt.Post":       true,
+	"ForEachStmt.Key":    true,
+	"ReturnStmt.Value":   true,
+	"BreakStmt.Label":    true,
+	"ContinueStmt.Label": true,
+	"Type.Key":           true,
+	"Type.Value":         true,
+}
+
+// check reports a field the decoded node needs but is missing, or fields that
+// don't agree, like the kind and the name of a type. The error has the
+// position of the node if it is given.
+func (d *decoder) check(node reflect.Value, path string) error {
+	pos := token.Pos{File: d.filename}
+	if n, ok := node.Interface().(Node); ok && n.Pos().IsValid() {
+		pos = n.Pos()
+	}
+	errorf := func(format string, args ...any) error {
+		return d.errorAt(pos, path, format, args...)
+	}
+
+	v := node.Elem()
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		k := field.Type.Kind()
+		if field.Anonymous || k != reflect.Pointer && k != reflect.Interface || !v.Field(i).IsNil() {
+			continue
+		}
+		if !optional[v.Type().Name()+"."+field.Name] {
+			return errorf("%s түйінінің %q өрісі берілмеген", v.Type().Name(), jsonName(field.Name))
+		}
+	}
+
+	switch n := node.Interface().(type) {
+	case *Type:
+		if !nameFits(n.Name.Value, n.Kind) {
+			return errorf("%q атты тип %s түрлі бола алмайды", n.Name.Value, n.Kind)
+		}
+		if (n.Kind == TMap) != (n.Key != nil && n.Value != nil) || n.Kind != TMap && (n.Key != nil || n.Value != nil) {
+			return errorf("кілт пен мән типтері тек сөздікте және екеуі бірге беріледі")
+		}
+	case *ForStmt:
+		if (n.Init == nil) != (n.Post == nil) || n.Init != nil && n.Cond == nil {
+			return errorf("қайталаның init, cond және post өрістері бірге беріледі, шартпен қайталауда тек cond беріледі")
+		}
+	case *MapExpr:
+		if len(n.Keys) != len(n.Values) {
+			return errorf("сөздіктің кілттері мен мәндерінің саны бірдей болуы керек")
+		}
+	case *LabeledStmt:
+		switch n.Stmt.(type) {
+		case *ForStmt, *ForEachStmt:
+		default:
+			return errorf("белгі тек қайталаға жазылады")
+		}
+	}
+	return nil
+}
+
+// nameFits reports whether a type called name can be of kind in one of the
+// dialects.
+func nameFits(name string, kind Kind) bool {
+	if name == "" {
+		return false
+	}
+	for d := range primitiveTypes {
+		if GetKind(name, token.Dialect(d)) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeStruct sets the fields of the struct out from the object m.
+func (d *decoder) decodeStruct(m map[string]any, out reflect.Value, path string) error {
+	fields := make(map[string]reflect.Value)
+	structFields(out, fields)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		if key == "node" {
+			continue
+		}
+		v := m[key]
+		field, ok := fields[key]
+		if !ok {
+			return d.errorf(path, "%s түйінінде %q өрісі жоқ", out.Type().Name(), key)
+		}
+		if err := d.decode(v, field, path+"."+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func structFields(v reflect.Value, fields map[string]reflect.Value) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			structFields(v.Field(i), fields)
+			continue
+		}
+		fields[jsonName(field.Name)] = v.Field(i)
+	}
+}
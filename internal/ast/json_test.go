@@ -0,0 +1,108 @@
+package ast_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/testutils"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		decls, err := parser.New(name, contents).Parse()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var out bytes.Buffer
+		if err := ast.EncodeJSON(&out, decls); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		decoded, err := ast.DecodeJSON(name, out.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(decoded, decls) {
+			t.Errorf("%s changed after writing as JSON and reading back", name)
+		}
+		var again bytes.Buffer
+		ast.EncodeJSON(&again, decoded)
+		if !bytes.Equal(again.Bytes(), out.Bytes()) {
+			t.Errorf("%s is written differently the second time", name)
+		}
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	src := `[{"node": "FuncDecl",
+		"name": {"node": "NameExpr", "value": "негізгі"},
+		"returnType": {"node": "Type", "kind": "ештеңе", "name": {"node": "NameExpr", "value": "ештеңе"}},
+		"body": [{"node": "CallStmt", "callExpr": {"node": "CallExpr",
+			"func": {"node": "NameExpr", "value": "жаз"},
+			"args": [{"node": "OpExpr", "op": "+", "left": {"node": "IntExpr", "value": 1}, "right": {"node": "IntExpr", "value": 2}}]}}]}]`
+	decls, err := ast.DecodeJSON("test.json", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree strings.Builder
+	ast.Fprint(&tree, decls)
+	want := `FuncDecl
+  name: NameExpr "негізгі"
+  returnType: Type
+    kind: "ештеңе"
+    name: NameExpr "ештеңе"
+    isArray: false
+    arrayLen: 0
+  body: [1]
+    0: CallStmt
+      callExpr: CallExpr
+        func: NameExpr "жаз"
+        args: [1]
+          0: OpExpr
+            op: "+"
+            left: IntExpr 1
+            right: IntExpr 2
+`
+	if tree.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", tree.String(), want)
+	}
+
+	for _, src := range []string{
+		`{}`,
+		`[{"node": "Барлық"}]`,
+		`[{"node": "IntExpr", "value": 1}]`,
+		`[{"node": "FuncDecl", "name": {"node": "IntExpr"}}]`,
+		`[{"node": "FuncDecl", "аты": "негізгі"}]`,
+		`[{"node": "FuncDecl", "returnType": {"node": "Type", "kind": "бүтінн"}}]`,
+		`[{"node": "FuncDecl", "body": [{"node": "ReturnStmt", "value": {"node": "OpExpr", "op": "**"}}]}]`,
+	} {
+		if _, err := ast.DecodeJSON("test.json", []byte(src)); !errors.Is(err, ast.ErrInvalidJSON) {
+			t.Errorf("%s: expected %v, got %v", src, ast.ErrInvalidJSON, err)
+		}
+	}
+
+	name := `"name": {"node": "NameExpr", "value": "негізгі"}`
+	void := `"returnType": {"node": "Type", "kind": "ештеңе", "name": {"node": "NameExpr", "value": "ештеңе"}}`
+	for _, src := range []string{
+		`[{"node": "FuncDecl", ` + name + `, "body": []}]`,
+		`[{"node": "FuncDecl", ` + void + `, "body": []}]`,
+		`[{"node": "FuncDecl", ` + name + `, ` + void + `, "body": [{"node": "CallStmt"}]}]`,
+		`[{"node": "FuncDecl", ` + name + `, ` + void + `, "body": [null]}]`,
+		`[{"node": "FuncDecl", ` + name + `, ` + void + `, "body": [{"node": "ReturnStmt", "value": {"node": "OpExpr", "op": "+", "right": {"node": "IntExpr", "value": 1}}}]}]`,
+		`[{"node": "FuncDecl", ` + name + `, "returnType": {"node": "Type", "kind": "бүтін"}, "body": []}]`,
+		`[{"node": "FuncDecl", ` + name + `, "returnType": {"node": "Type", "kind": "бүтін", "name": {"node": "NameExpr", "value": "жол"}}, "body": []}]`,
+		`[{"node": "FuncDecl", ` + name + `, "returnType": {"node": "Type", "kind": "сөздік", "name": {"node": "NameExpr", "value": "сөздік"}}, "body": []}]`,
+		`[{"node": "FuncDecl", ` + name + `, ` + void + `, "body": [{"node": "VarStmt", "name": {"node": "NameExpr", "value": "а"}}]}]`,
+	} {
+		_, err := ast.DecodeJSON("test.json", []byte(src))
+		if !errors.Is(err, ast.ErrInvalidJSON) {
+			t.Errorf("%s: expected %v, got %v", src, ast.ErrInvalidJSON, err)
+		} else if !strings.Contains(err.Error(), "test.json") {
+			t.Errorf("%s: expected the error to have the position, got %v", src, err)
+		}
+	}
+}
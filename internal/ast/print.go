@@ -0,0 +1,87 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Fprint writes decls to w as an indented tree, a node per line with its
+// range and its fields under it:
+//
+//	FuncDecl 1:1-3:2
+//	  name: NameExpr "негізгі" 1:16-1:23
+//	  returnType: Type 1:9-1:15
+//	    kind: "ештеңе"
+//
+// The fields are named as in the JSON form of the program.
+func Fprint(w io.Writer, decls []Decl) error {
+	v, err := encode(reflect.ValueOf(decls))
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, decl := range v.([]any) {
+		printValue(&b, decl, 0)
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// printValue writes the rest of the line of a value and the lines of its
+// fields or elements under it.
+func printValue(b *strings.Builder, v any, depth int) {
+	switch v := v.(type) {
+	case object:
+		b.WriteString(v.get("node").(string))
+		// a node with one scalar field, like a name, fits in its line
+		var fields []member
+		for _, m := range v {
+			switch m.key {
+			case "node", "pos", "end":
+				continue
+			}
+			fields = append(fields, m)
+		}
+		inline := false
+		if len(fields) == 1 {
+			switch value := fields[0].value.(type) {
+			case string:
+				fmt.Fprintf(b, " %q", value)
+				inline = true
+			case int64, float64, bool:
+				fmt.Fprintf(b, " %v", value)
+				inline = true
+			}
+		}
+		if pos, ok := v.get("pos").(object); ok {
+			fmt.Fprintf(b, " %v:%v", pos.get("line"), pos.get("col"))
+			if end, ok := v.get("end").(object); ok {
+				fmt.Fprintf(b, "-%v:%v", end.get("line"), end.get("col"))
+			}
+		}
+		b.WriteByte('\n')
+		if inline {
+			return
+		}
+		for _, m := range fields {
+			if list, ok := m.value.([]any); m.value == nil || ok && len(list) == 0 {
+				// leave out what is not there
+				continue
+			}
+			fmt.Fprintf(b, "%s%s: ", strings.Repeat("  ", depth+1), m.key)
+			printValue(b, m.value, depth+1)
+		}
+	case []any:
+		fmt.Fprintf(b, "[%d]\n", len(v))
+		for i, elem := range v {
+			fmt.Fprintf(b, "%s%d: ", strings.Repeat("  ", depth+1), i)
+			printValue(b, elem, depth+1)
+		}
+	case string:
+		fmt.Fprintf(b, "%q\n", v)
+	default:
+		fmt.Fprintf(b, "%v\n", v)
+	}
+}
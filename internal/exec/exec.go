@@ -3,8 +3,10 @@ package exec
 import (
 	"io"
 
+	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/machine"
 	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/types"
 )
 
 func Exec(stdout io.Writer, filename string, source []byte) error {
@@ -13,7 +15,16 @@ func Exec(stdout io.Writer, filename string, source []byte) error {
 	if err != nil {
 		return err
 	}
-	program, err := machine.New(stdout, decls)
+	return Run(stdout, source, decls)
+}
+
+// Run checks and runs declarations parsed from source. source is only used
+// to show the code of errors, it is nil for a program given as JSON.
+func Run(stdout io.Writer, source []byte, decls []ast.Decl) error {
+	if err := types.Check(source, decls); err != nil {
+		return err
+	}
+	program, err := machine.New(stdout, source, decls)
 	if err != nil {
 		return err
 	}
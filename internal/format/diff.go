@@ -0,0 +1,107 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// context is how many unchanged lines are shown around a change.
+const context = 3
+
+// Diff returns the changes from a to b in the unified diff format, or an
+// empty string if they are equal. The lines are matched by their longest
+// common subsequence, programs of students are small enough for that.
+func Diff(name string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	x, y := lines(a), lines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte // ' ', '-' or '+'
+		line string
+		i, j int // line numbers in a and b before the edit
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			edits = append(edits, edit{' ', x[i], i, j})
+			i++
+			j++
+		case j == len(y) || i < len(x) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', x[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', y[j], i, j})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", name, name)
+	for start := 0; start < len(edits); {
+		// a hunk is the changes closer than 2*context lines to each other
+		// and the unchanged lines around them
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		end := start
+		for same := 0; end < len(edits) && same <= 2*context; end++ {
+			if edits[end].op == ' ' {
+				same++
+			} else {
+				same = 0
+			}
+		}
+		for end > start && edits[end-1].op == ' ' {
+			end--
+		}
+		first, last := max(start-context, 0), min(end+context, len(edits))
+		hunk := edits[first:last]
+		var aLen, bLen int
+		for _, e := range hunk {
+			if e.op != '+' {
+				aLen++
+			}
+			if e.op != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", hunk[0].i+1, aLen, hunk[0].j+1, bLen)
+		for _, e := range hunk {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.line)
+		}
+		start = last
+	}
+	return out.String()
+}
+
+func lines(text []byte) []string {
+	s := strings.TrimSuffix(string(text), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
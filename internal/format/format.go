@@ -0,0 +1,476 @@
+// Package format prints programs in the canonical layout of the examples:
+// tabs for indentation, spaces around binary operators, the opening brace
+// on the line of its statement and ';' after every simple statement.
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+// Source formats the program src. Programs with syntax errors are not
+// formatted, the errors are returned.
+func Source(filename string, src []byte) ([]byte, error) {
+	decls, err := parser.New(filename, src).Parse()
+	if err != nil {
+		return nil, err
+	}
+	p := printer{src: src, braces: make(map[int]int)}
+	p.scanComments(filename)
+	p.decls(decls)
+	return p.out.Bytes(), nil
+}
+
+type comment struct {
+	text string
+	pos  token.Pos
+}
+
+type printer struct {
+	src     []byte
+	out     bytes.Buffer
+	dialect token.Dialect
+	indent  int
+
+	// the comments are not in the AST, they are printed before the first
+	// node after them or at the end of the line of the node before them
+	comments []comment
+	next     int // the first comment not printed yet
+	// braces maps the offsets of '{' to the offsets of their '}', to find
+	// the comments at the end of a block
+	braces map[int]int
+	// lastLine is the source line the last printed node or comment ends
+	// on, a blank line before the next one in the source is kept
+	lastLine int
+}
+
+// scanComments collects the comments and matches the braces of the source,
+// and finds out its dialect.
+func (p *printer) scanComments(filename string) {
+	s := scanner.New(filename, p.src, scanner.ScanComments)
+	var open []int
+	for s.Scan() {
+		switch s.Tok() {
+		case token.COMMENT:
+			p.comments = append(p.comments, comment{s.Lit(), s.Pos()})
+		case token.LBRACE:
+			open = append(open, s.Pos().Offset)
+		case token.RBRACE:
+			if n := len(open); n != 0 {
+				p.braces[open[n-1]] = s.Pos().Offset
+				open = open[:n-1]
+			}
+		}
+	}
+	p.dialect = s.Dialect()
+}
+
+// blockEnd returns the offset of the '}' closing the first block opened
+// at or after offset.
+func (p *printer) blockEnd(offset int) int {
+	for i := offset; i < len(p.src); i++ {
+		if end, ok := p.braces[i]; ok && p.src[i] == '{' {
+			return end
+		}
+	}
+	return len(p.src)
+}
+
+func (p *printer) print(args ...any) {
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			p.out.WriteString(arg)
+		case token.Token:
+			p.out.WriteString(arg.In(p.dialect))
+		default:
+			fmt.Fprint(&p.out, arg)
+		}
+	}
+}
+
+// line starts a new line at the current indentation, keeping a blank line
+// before the source line at if the source has one.
+func (p *printer) line(at int) {
+	if p.lastLine != 0 && at > p.lastLine+1 {
+		p.out.WriteByte('\n')
+	}
+	p.out.WriteString(strings.Repeat("\t", p.indent))
+}
+
+// commentsBefore prints the comments before offset on their own lines.
+func (p *printer) commentsBefore(offset int) {
+	for ; p.next < len(p.comments) && p.comments[p.next].pos.Offset < offset; p.next++ {
+		c := p.comments[p.next]
+		p.line(c.pos.Line)
+		p.print(c.text, "\n")
+		p.lastLine = c.pos.Line + strings.Count(c.text, "\n")
+	}
+}
+
+// endLine ends the line of a node ending at end, with the comment after
+// the node if it is on the same line.
+func (p *printer) endLine(end token.Pos) {
+	p.lastLine = end.Line
+	if p.next < len(p.comments) {
+		if c := p.comments[p.next]; c.pos.Line == end.Line && !strings.Contains(c.text, "\n") {
+			p.print(" ", c.text)
+			p.next++
+		}
+	}
+	p.out.WriteByte('\n')
+}
+
+func (p *printer) decls(decls []ast.Decl) {
+	for i, decl := range decls {
+		if i > 0 {
+			// functions and structures are always apart
+			_, prevVar := decls[i-1].(*ast.VarDecl)
+			_, isVar := decl.(*ast.VarDecl)
+			if !prevVar || !isVar {
+				p.out.WriteByte('\n')
+				p.lastLine = 0
+			}
+		}
+		p.commentsBefore(decl.Pos().Offset)
+		p.line(decl.Pos().Line)
+		switch decl := decl.(type) {
+		case *ast.VarDecl:
+			p.varStmt(decl.Var)
+			p.print(";")
+		case *ast.FuncDecl:
+			p.print(token.FUNC, " ")
+			p.typ(decl.ReturnType)
+			p.print(" ", decl.Name.Value, "(")
+			for i, arg := range decl.Args {
+				if i > 0 {
+					p.print(", ")
+				}
+				p.print(arg.Name, " ")
+				p.typ(arg.Type)
+			}
+			p.print(") ")
+			p.block(decl.Body, decl.Name.End().Offset)
+		case *ast.StructDecl:
+			p.print(token.STRUCT, " ", decl.Name.Value, " {\n")
+			p.lastLine = decl.Name.Pos().Line
+			p.indent++
+			for _, field := range decl.Fields {
+				p.commentsBefore(field.Type.Pos().Offset)
+				p.line(field.Type.Pos().Line)
+				p.print(field.Name, " ")
+				p.typ(field.Type)
+				p.print(",")
+				p.endLine(field.Type.End())
+			}
+			p.commentsBefore(decl.End().Offset)
+			p.indent--
+			p.print("}")
+		}
+		p.endLine(decl.End())
+	}
+	p.commentsBefore(len(p.src))
+}
+
+// block prints the statements of the block opened at or after offset and
+// the closing brace, the line is ended by the caller.
+func (p *printer) block(stmts []ast.Stmt, offset int) {
+	end := p.blockEnd(offset)
+	p.print("{\n")
+	p.lastLine = 0
+	p.indent++
+	p.stmts(stmts)
+	p.commentsBefore(end)
+	p.indent--
+	p.print(strings.Repeat("\t", p.indent), "}")
+}
+
+// stmts prints stmts on their own lines with the comments before them.
+func (p *printer) stmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		p.commentsBefore(stmt.Pos().Offset)
+		p.line(stmt.Pos().Line)
+		p.stmt(stmt)
+		p.endLine(stmt.End())
+	}
+}
+
+func (p *printer) stmt(stmt ast.Stmt) {
+	switch stmt := stmt.(type) {
+	case *ast.VarStmt, *ast.AssignStmt, *ast.IncDecStmt, *ast.CallStmt:
+		p.simpleStmt(stmt)
+		p.print(";")
+	case *ast.IfStmt:
+		p.ifStmt(stmt)
+	case *ast.ForStmt:
+		switch {
+		case stmt.Cond == nil:
+			p.print(token.FOR, " ")
+			p.block(stmt.Body, stmt.Pos().Offset)
+		case stmt.Init == nil:
+			p.print(token.FOR, "(")
+			p.expr(stmt.Cond)
+			p.print(") ")
+			p.block(stmt.Body, stmt.Cond.End().Offset)
+		default:
+			p.print(token.FOR, "(")
+			p.varStmt(stmt.Init)
+			p.print("; ")
+			p.expr(stmt.Cond)
+			p.print("; ")
+			p.simpleStmt(stmt.Post)
+			p.print(") ")
+			p.block(stmt.Body, stmt.Post.End().Offset)
+		}
+	case *ast.SwitchStmt:
+		p.switchStmt(stmt)
+	case *ast.ForEachStmt:
+		p.print(token.FOR, "(")
+		if stmt.Key != nil {
+			p.print(stmt.Key.Value, ", ")
+		}
+		p.print(stmt.Value.Value, " : ")
+		p.expr(stmt.X)
+		p.print(") ")
+		p.block(stmt.Body, stmt.X.End().Offset)
+	case *ast.ReturnStmt:
+		p.print(token.RETURN)
+		if stmt.Value != nil {
+			p.print(" ")
+			p.expr(stmt.Value)
+		}
+		p.print(";")
+	case *ast.LabeledStmt:
+		p.print(stmt.Label.Value, ": ")
+		p.stmt(stmt.Stmt)
+	case *ast.BreakStmt:
+		p.print(token.BREAK)
+		if stmt.Label != nil {
+			p.print(" ", stmt.Label.Value)
+		}
+		p.print(";")
+	case *ast.ContinueStmt:
+		p.print(token.CONTINUE)
+		if stmt.Label != nil {
+			p.print(" ", stmt.Label.Value)
+		}
+		p.print(";")
+	}
+}
+
+func (p *printer) ifStmt(stmt *ast.IfStmt) {
+	p.print(token.IF, "(")
+	p.expr(stmt.Cond)
+	p.print(") ")
+	p.block(stmt.Then, stmt.Cond.End().Offset)
+	switch els := stmt.Else.(type) {
+	case *ast.IfStmt:
+		p.print(" ", token.ELSE, " ")
+		p.ifStmt(els)
+	case ast.Stmts:
+		p.print(" ", token.ELSE, " ")
+		p.block(els, p.blockEnd(stmt.Cond.End().Offset)+1)
+	}
+}
+
+// switchStmt prints a таңда with жағдай and әдепкі on the indentation of
+// таңда and the statements of the cases one level deeper.
+func (p *printer) switchStmt(stmt *ast.SwitchStmt) {
+	end := p.blockEnd(stmt.Tag.End().Offset)
+	p.print(token.SWITCH, "(")
+	p.expr(stmt.Tag)
+	p.print(") {\n")
+	p.lastLine = 0
+	for _, clause := range stmt.Cases {
+		p.commentsBefore(clause.Pos().Offset)
+		p.line(clause.Pos().Line)
+		head := clause.Pos()
+		if clause.Values == nil {
+			p.print(token.DEFAULT, ":")
+		} else {
+			p.print(token.CASE, " ")
+			p.exprList(clause.Values)
+			p.print(":")
+			head = clause.Values[len(clause.Values)-1].End()
+		}
+		p.endLine(head)
+		p.indent++
+		p.stmts(clause.Body)
+		p.indent--
+	}
+	p.commentsBefore(end)
+	p.print(strings.Repeat("\t", p.indent), "}")
+}
+
+func (p *printer) varStmt(stmt *ast.VarStmt) {
+	if stmt.Const {
+		p.print(token.CONST)
+	} else {
+		p.print(token.VAR)
+	}
+	p.print(" ", stmt.Name.Value, " ")
+	p.typ(stmt.Type)
+	if stmt.Val != nil {
+		p.print(" = ")
+		p.expr(stmt.Val)
+	}
+}
+
+// simpleStmt prints a statement that can be in the header of a loop,
+// without the ';' after it.
+func (p *printer) simpleStmt(stmt ast.Stmt) {
+	switch stmt := stmt.(type) {
+	case *ast.VarStmt:
+		p.varStmt(stmt)
+	case *ast.AssignStmt:
+		p.expr(stmt.Var)
+		if stmt.Op != token.ILLEGAL {
+			p.print(" ", stmt.Op.String(), "= ")
+		} else {
+			p.print(" = ")
+		}
+		p.expr(stmt.Val)
+	case *ast.IncDecStmt:
+		p.expr(stmt.Var)
+		p.print(stmt.Op.String())
+	case *ast.CallStmt:
+		p.expr(stmt.CallExpr)
+	}
+}
+
+func (p *printer) typ(typ *ast.Type) {
+	if typ.IsList() {
+		p.print("[]")
+	} else if typ.IsArray {
+		p.print("[", typ.ArrayLen, "]")
+	}
+	p.print(typ.Name.Value)
+	if typ.Kind == ast.TMap {
+		p.print("[")
+		p.typ(typ.Key)
+		p.print("]")
+		p.typ(typ.Value)
+	}
+}
+
+func (p *printer) expr(expr ast.Expr) {
+	switch expr := expr.(type) {
+	case *ast.NameExpr:
+		p.print(expr.Value)
+	case *ast.IntExpr:
+		if !p.literal(expr) {
+			p.print(strconv.Itoa(expr.Value))
+		}
+	case *ast.FloatExpr:
+		if !p.literal(expr) {
+			lit := strconv.FormatFloat(float64(expr.Value), 'g', -1, 32)
+			if !strings.ContainsAny(lit, ".e") {
+				lit += ".0"
+			}
+			p.print(lit)
+		}
+	case *ast.StringExpr:
+		if !p.literal(expr) {
+			p.print(`"`, quote(expr.Value), `"`)
+		}
+	case *ast.InterpExpr:
+		if !p.literal(expr) {
+			p.print(`"`)
+			for _, part := range expr.Parts {
+				if text, ok := part.(*ast.StringExpr); ok {
+					p.print(quote(text.Value))
+					continue
+				}
+				p.print("{")
+				p.expr(part)
+				p.print("}")
+			}
+			p.print(`"`)
+		}
+	case *ast.BoolExpr:
+		if expr.Value {
+			p.print(token.TRUE)
+		} else {
+			p.print(token.FALSE)
+		}
+	case *ast.ArrayExpr:
+		p.print("{")
+		p.exprList(expr.Elements)
+		p.print("}")
+	case *ast.MapExpr:
+		p.print("{")
+		for i := range expr.Keys {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expr(expr.Keys[i])
+			p.print(": ")
+			p.expr(expr.Values[i])
+		}
+		p.print("}")
+	case *ast.CallExpr:
+		p.expr(expr.Func)
+		p.print("(")
+		p.exprList(expr.Args)
+		p.print(")")
+	case *ast.SelectorExpr:
+		p.expr(expr.Struct)
+		p.print(".", expr.Field.Value)
+	case *ast.ArrayAccessExpr:
+		p.expr(expr.Array)
+		p.print("[")
+		p.expr(expr.Index)
+		p.print("]")
+	case *ast.ParenExpr:
+		p.print("(")
+		p.expr(expr.X)
+		p.print(")")
+	case *ast.RangeExpr:
+		p.expr(expr.From)
+		p.print(token.DOTDOT)
+		p.expr(expr.To)
+	case *ast.UnaryOpExpr:
+		p.print(expr.Op.String())
+		if inner, ok := expr.Operand.(*ast.UnaryOpExpr); ok && inner.Op == token.SUB {
+			p.print(" ") // - -х is not --х
+		}
+		p.expr(expr.Operand)
+	case *ast.OpExpr:
+		p.expr(expr.Left)
+		p.print(" ", expr.Op.String(), " ")
+		p.expr(expr.Right)
+	}
+}
+
+// literal prints a literal as it is written in the source, so that 0xFF
+// stays 0xFF and escapes stay escapes. Nodes made without source, like
+// the ones read from JSON, are printed from their values.
+func (p *printer) literal(expr ast.Expr) bool {
+	start, end := expr.Pos().Offset, expr.End().Offset
+	if !expr.Pos().IsValid() || end > len(p.src) {
+		return false
+	}
+	p.out.Write(p.src[start:end])
+	return true
+}
+
+// quote escapes the text of a string literal.
+func quote(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "\n", `\n`, "\t", `\t`).Replace(text)
+}
+
+func (p *printer) exprList(exprs []ast.Expr) {
+	for i, expr := range exprs {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.expr(expr)
+	}
+}
@@ -0,0 +1,146 @@
+package format_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nurtai325/qurtc/internal/format"
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/testutils"
+	"github.com/nurtai325/qurtc/internal/token"
+	"github.com/nurtai325/qurtc/internal/translit"
+)
+
+func TestExamplesFormatted(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		out, err := format.Source(name, contents)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(out, contents) {
+			t.Errorf("%s is not formatted:\n%s", name, format.Diff(name, contents, out))
+		}
+	})
+}
+
+func TestFormat(t *testing.T) {
+	src := `// басы
+тұрақты а бүтін = 0xFF
+тұрақты б бүтін = 2
+құрылым нүкте { х бүтін,
+у [3]бөлшек, // у
+}
+функция ештеңе негізгі(){
+  айнымалы с жол="а{а}\t" // түсініктеме
+
+
+    егер (а>б){жаз(-а+б*2)}әйтпесе егер(!иә){
+      /* блок */
+      тоқта
+    } әйтпесе { қайтар }
+    қайтала(айнымалы i бүтін=0;i<3;i++){жаз((i+1)*2)
+      с+="!"
+    // соңы
+    }
+}
+// файл соңы
+`
+	want := `// басы
+тұрақты а бүтін = 0xFF;
+тұрақты б бүтін = 2;
+
+құрылым нүкте {
+	х бүтін,
+	у [3]бөлшек, // у
+}
+
+функция ештеңе негізгі() {
+	айнымалы с жол = "а{а}\t"; // түсініктеме
+
+	егер(а > б) {
+		жаз(-а + б * 2);
+	} әйтпесе егер(!иә) {
+		/* блок */
+		тоқта;
+	} әйтпесе {
+		қайтар;
+	}
+	қайтала(айнымалы i бүтін = 0; i < 3; i++) {
+		жаз((i + 1) * 2);
+		с += "!";
+		// соңы
+	}
+}
+// файл соңы
+`
+	got, err := format.Source("test.құрт", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if _, err := format.Source("test.құрт", []byte("функция ештеңе негізгі( {\n}\n")); err == nil {
+		t.Error("expected a syntax error")
+	}
+}
+
+func TestFormatUnaryOps(t *testing.T) {
+	src := "функция ештеңе негізгі() {\n\tжаз(- -1, -(-2), - - -3, !!иә);\n}\n"
+	got, err := format.Source("test.құрт", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != src {
+		t.Errorf("got\n%s\nwant\n%s", got, src)
+	}
+	if _, err := parser.New("test.құрт", got).Parse(); err != nil {
+		t.Errorf("formatted source doesn't parse: %v", err)
+	}
+}
+
+func TestFormatDialect(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		latin, err := translit.Translit(name, contents, token.Latin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := format.Source(name, latin)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(out, latin) {
+			t.Errorf("%s in Latin changed after formatting:\n%s", name, format.Diff(name, latin, out))
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\nекі\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := `--- test.құрт
++++ test.құрт
+@@ -1,5 +1,5 @@
+ 1
+-2
++екі
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := format.Diff("test.құрт", []byte(a), []byte(b)); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := format.Diff("test.құрт", []byte(a), []byte(a)); got != "" {
+		t.Errorf("expected no diff for equal files, got\n%s", got)
+	}
+	if !strings.HasPrefix(format.Diff("x", nil, []byte("а\n")), "--- x\n+++ x\n@@ -1,0 +1,1 @@\n+а\n") {
+		t.Errorf("unexpected diff from an empty file:\n%s", format.Diff("x", nil, []byte("а\n")))
+	}
+}
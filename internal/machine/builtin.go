@@ -2,28 +2,133 @@ package machine
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/types"
 )
 
 func builtinFuncs(m *machine) map[string]*ast.BuiltinFuncDecl {
-	builtinPrintName, builtinPrint := builtinPrint(m)
-	return map[string]*ast.BuiltinFuncDecl{
-		builtinPrintName: builtinPrint,
+	funcs := make(map[string]*ast.BuiltinFuncDecl)
+	for _, builtin := range []func(m *machine) (string, *ast.BuiltinFuncDecl){
+		builtinPrint, builtinLen, builtinAppend, builtinRemove, builtinSlice, builtinHas,
+	} {
+		name, decl := builtin(m)
+		funcs[name] = decl
 	}
+	return funcs
 }
 
 func builtinPrint(m *machine) (string, *ast.BuiltinFuncDecl) {
 	name := "жаз"
 	return name, &ast.BuiltinFuncDecl{
 		Name: &ast.NameExpr{Value: name},
-		Body: func(args ...any) error {
+		Body: func(args ...any) (any, error) {
 			_, err := fmt.Fprintln(m.stdout, args...)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			return nil
+			return nil, nil
+		},
+	}
+}
+
+func builtinLen(m *machine) (string, *ast.BuiltinFuncDecl) {
+	name := "ұзындық"
+	return name, &ast.BuiltinFuncDecl{
+		Name: &ast.NameExpr{Value: name},
+		Body: func(args ...any) (any, error) {
+			if len(args) != 1 {
+				return nil, ErrFuncArgMismatch
+			}
+			switch v := args[0].(type) {
+			case *types.Array:
+				return types.Int(v.Len()), nil
+			case *types.Map:
+				return types.Int(v.Len()), nil
+			case types.String:
+				return types.Int(utf8.RuneCountInString(string(v))), nil
+			}
+			return nil, ErrFuncArgMismatch
+		},
+	}
+}
+
+func builtinAppend(m *machine) (string, *ast.BuiltinFuncDecl) {
+	name := "қос"
+	return name, &ast.BuiltinFuncDecl{
+		Name: &ast.NameExpr{Value: name},
+		Body: func(args ...any) (any, error) {
+			if len(args) < 2 {
+				return nil, ErrFuncArgMismatch
+			}
+			list, ok := args[0].(*types.Array)
+			if !ok {
+				return nil, ErrFuncArgMismatch
+			}
+			vals := make([]types.Type, 0, len(args)-1)
+			for _, arg := range args[1:] {
+				vals = append(vals, arg.(types.Type))
+			}
+			return nil, list.Append(vals...)
+		},
+	}
+}
+
+func builtinRemove(m *machine) (string, *ast.BuiltinFuncDecl) {
+	name := "өшір"
+	return name, &ast.BuiltinFuncDecl{
+		Name: &ast.NameExpr{Value: name},
+		Body: func(args ...any) (any, error) {
+			if len(args) != 2 {
+				return nil, ErrFuncArgMismatch
+			}
+			if mp, ok := args[0].(*types.Map); ok {
+				mp.Delete(args[1].(types.Type))
+				return nil, nil
+			}
+			list, ok := args[0].(*types.Array)
+			index, indexOk := args[1].(types.Int)
+			if !ok || !indexOk {
+				return nil, ErrFuncArgMismatch
+			}
+			return nil, list.Remove(int(index))
+		},
+	}
+}
+
+func builtinSlice(m *machine) (string, *ast.BuiltinFuncDecl) {
+	name := "бөлік"
+	return name, &ast.BuiltinFuncDecl{
+		Name: &ast.NameExpr{Value: name},
+		Body: func(args ...any) (any, error) {
+			if len(args) != 3 {
+				return nil, ErrFuncArgMismatch
+			}
+			list, ok := args[0].(*types.Array)
+			start, startOk := args[1].(types.Int)
+			end, endOk := args[2].(types.Int)
+			if !ok || !startOk || !endOk {
+				return nil, ErrFuncArgMismatch
+			}
+			return list.Slice(int(start), int(end))
+		},
+	}
+}
+
+func builtinHas(m *machine) (string, *ast.BuiltinFuncDecl) {
+	name := "бар"
+	return name, &ast.BuiltinFuncDecl{
+		Name: &ast.NameExpr{Value: name},
+		Body: func(args ...any) (any, error) {
+			if len(args) != 2 {
+				return nil, ErrFuncArgMismatch
+			}
+			mp, ok := args[0].(*types.Map)
+			if !ok {
+				return nil, ErrFuncArgMismatch
+			}
+			return types.Bool(mp.Has(args[1].(types.Type))), nil
 		},
 	}
 }
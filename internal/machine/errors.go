@@ -2,6 +2,13 @@ package machine
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/token"
 )
 
 var (
@@ -20,9 +27,53 @@ var (
 	ErrArrAccessOnNotArr       = errors.New("тізім мүшесін алу операциясы тек тізімдерге ғана болады және индекс мәні бүтін шығуы керек")
 	ErrStructAccessNotOnStruct = errors.New("құрылым мүшесін алу операциясы тек құрылымдарға ғана болады")
 	ErrInvalidAssign           = errors.New("айнымалы мәнін өзгертудің ережелері сақталмаған")
+	ErrAssignToConst           = errors.New("тұрақтының мәнін өзгертуге болмайды")
 	ErrIfWithNoBool            = errors.New("егер нұсқауының шарты тек шын типі бола алады")
 	ErrInvalidElse             = errors.New("егер нұсқауының әйтпесе бөлігі ережеге сай емес")
 	ErrInvalidFor              = errors.New("қайтала нұсқауын жасаудың ережесі сақталмаған")
 	ErrContinueInNotLoop       = errors.New("өткіз нұсқауын тек қайтала нұсқауының денесінде қолдануға болады")
-	ErrBreakInNotLoop          = errors.New("тоқта нұсқауын тек қайтала нұсқауының денесінде қолдануға болады")
+	ErrBreakInNotLoop          = errors.New("тоқта нұсқауын тек қайтала немесе таңда нұсқауының денесінде қолдануға болады")
+	ErrUnknownLabel            = errors.New("бұл белгімен қайтала жоқ")
+	ErrReturnTypeMismatch      = errors.New("қайтарылған мәннің типі функцияның жарияланған типіне сай емес")
+	ErrMissingReturn           = errors.New("функция мән қайтармай аяқталды, бірақ оның типі ештеңе емес")
 )
+
+// RuntimeError is an error that happened while running the program.
+type RuntimeError struct {
+	Pos     token.Pos
+	End     token.Pos
+	Err     error
+	Stack   []string // called functions, outermost first
+	Snippet string   // source lines with the error marked, may be empty
+}
+
+func (e *RuntimeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Орындалу қатесі (файл: %s, жол: %d, қатар: %d): %s\n", e.Pos.File, e.Pos.Line, e.Pos.Col, e.Err.Error())
+	b.WriteString(e.Snippet)
+	if len(e.Stack) != 0 {
+		fmt.Fprintf(&b, "Шақырулар тізбегі: %s\n", strings.Join(e.Stack, " → "))
+	}
+	return b.String()
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
+}
+
+// errorAt attaches the position of node and the current call stack to err.
+// Errors that already have a position are returned as is, so the innermost
+// failing node is reported.
+func (m *machine) errorAt(node ast.Node, err error) error {
+	if rerr := (*RuntimeError)(nil); errors.As(err, &rerr) {
+		return err
+	}
+	pos, end := node.Pos(), node.End()
+	return &RuntimeError{
+		Pos:     pos,
+		End:     end,
+		Err:     err,
+		Stack:   slices.Clone(m.stack),
+		Snippet: scanner.Snippet(m.src, pos, end, 1),
+	}
+}
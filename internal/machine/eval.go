@@ -1,16 +1,35 @@
 package machine
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/parser"
 	"github.com/nurtai325/qurtc/internal/token"
 	"github.com/nurtai325/qurtc/internal/types"
 )
 
-func (m *machine) eval(exprScope *scope, expr ast.Expr) (types.Type, error) {
+func (m *machine) eval(exprScope *scope, expr ast.Expr) (_ types.Type, err error) {
+	defer func() {
+		if err != nil {
+			err = m.errorAt(expr, err)
+		}
+	}()
 	switch v := expr.(type) {
 	case *ast.StringExpr:
 		return types.String(v.Value), nil
+	case *ast.InterpExpr:
+		var b strings.Builder
+		for _, part := range v.Parts {
+			val, err := m.eval(exprScope, part)
+			if err != nil {
+				return nil, err
+			}
+			// values are written the same way жаз writes them
+			fmt.Fprint(&b, val)
+		}
+		return types.String(b.String()), nil
 	case *ast.IntExpr:
 		return types.Int(v.Value), nil
 	case *ast.FloatExpr:
@@ -32,11 +51,34 @@ func (m *machine) eval(exprScope *scope, expr ast.Expr) (types.Type, error) {
 			return nil, types.ErrNotSameType
 		}
 		return types.NewArray(elements)
+	case *ast.MapExpr:
+		mp := types.NewMap()
+		for i := range v.Keys {
+			key, err := m.eval(exprScope, v.Keys[i])
+			if err != nil {
+				return nil, err
+			}
+			val, err := m.eval(exprScope, v.Values[i])
+			if err != nil {
+				return nil, err
+			}
+			if err := mp.Set(key, val); err != nil {
+				return nil, err
+			}
+		}
+		return mp, nil
 	case *ast.ArrayAccessExpr:
 		res, err := m.eval(exprScope, v.Array)
 		if err != nil {
 			return nil, err
 		}
+		if mp, ok := res.(*types.Map); ok {
+			key, err := m.eval(exprScope, v.Index)
+			if err != nil {
+				return nil, err
+			}
+			return mp.Get(key)
+		}
 		arr, ok := res.(*types.Array)
 		if !ok {
 			return nil, ErrArrAccessOnNotArr
@@ -59,9 +101,6 @@ func (m *machine) eval(exprScope *scope, expr ast.Expr) (types.Type, error) {
 		if !ok {
 			return nil, ErrStructAccessNotOnStruct
 		}
-		if v.Field == nil {
-			return structVal, nil
-		}
 		return structVal.Get(v.Field.Value)
 	case *ast.CallExpr:
 		args, err := m.evalAll(exprScope, v.Args)
@@ -83,6 +122,8 @@ func (m *machine) eval(exprScope *scope, expr ast.Expr) (types.Type, error) {
 			return nil, err
 		}
 		return res, nil
+	case *ast.ParenExpr:
+		return m.eval(exprScope, v.X)
 	case *ast.UnaryOpExpr:
 		operand, err := m.eval(exprScope, v.Operand)
 		if err != nil {
@@ -117,28 +158,57 @@ func (m *machine) call(fn ast.Expr, args []types.Type) (types.Type, error) {
 			return nil, err
 		}
 		if builtinFunc != nil {
-			return nil, builtinFunc.Body(typesToAny(args)...)
+			res, err := builtinFunc.Body(typesToAny(args)...)
+			if res == nil || err != nil {
+				return nil, err
+			}
+			return res.(types.Type), nil
 		}
 		return nil, ErrCallNoFunc
 	}
 
-	currScope, err := newFuncScope(funcDecl, args)
+	currScope, err := newFuncScope(m.globals, funcDecl, args)
 	if err != nil {
 		return nil, err
 	}
-	for _, stmt := range funcDecl.Body {
-		retVal, err := m.exec(currScope, stmt)
-		if err != nil {
-			return nil, err
-		}
-		if retVal != nil {
-			return retVal, nil
+	m.stack = append(m.stack, funcDecl.Name.Value)
+	defer func() {
+		m.stack = m.stack[:len(m.stack)-1]
+	}()
+	j, err := m.execBlock(currScope, funcDecl.Body)
+	if err != nil {
+		return nil, err
+	}
+	if j != nil {
+		switch {
+		case j.tok == token.RETURN:
+			if !returnsType(j.val, funcDecl.ReturnType) {
+				return nil, m.errorAt(j.stmt, ErrReturnTypeMismatch)
+			}
+			return j.val, nil
+		case j.label != "":
+			return nil, m.errorAt(j.stmt, ErrUnknownLabel)
+		case j.tok == token.BREAK:
+			return nil, m.errorAt(j.stmt, ErrBreakInNotLoop)
+		default:
+			return nil, m.errorAt(j.stmt, ErrContinueInNotLoop)
 		}
 	}
-	// return nil because func body didn't return anything
+	if funcDecl.ReturnType.Kind != ast.TVoid || funcDecl.ReturnType.IsArray {
+		return nil, m.errorAt(funcDecl.Name, ErrMissingReturn)
+	}
 	return nil, nil
 }
 
+// returnsType reports whether val, nil for no value, can be returned from a
+// function declared to return typ.
+func returnsType(val types.Type, typ *ast.Type) bool {
+	if typ.Kind == ast.TVoid && !typ.IsArray {
+		return val == nil
+	}
+	return val != nil && types.IsOfType(val, typ)
+}
+
 func (m *machine) binary(op token.Token, x, y types.Type) (types.Type, error) {
 	if !types.IsSameType(x, y) {
 		return nil, ErrNotSameTypeOp
@@ -11,14 +11,21 @@ const mainName = "негізгі"
 
 type machine struct {
 	stdout       io.Writer
+	src          []byte
+	stack        []string // names of the functions being called, outermost first
 	structs      map[string]*ast.StructDecl
 	funcs        map[string]*ast.FuncDecl
 	builtinFuncs map[string]*ast.BuiltinFuncDecl
+	vars         []*ast.VarDecl // global variables in the order they are declared
+	globals      *scope
 }
 
-func New(stdout io.Writer, decls []ast.Decl) (*machine, error) {
+// New prepares decls to run. src is the source the declarations were parsed
+// from, it is used to show the failing line in runtime errors and may be nil.
+func New(stdout io.Writer, src []byte, decls []ast.Decl) (*machine, error) {
 	mch := machine{
 		stdout:  stdout,
+		src:     src,
 		structs: make(map[string]*ast.StructDecl),
 		funcs:   make(map[string]*ast.FuncDecl),
 	}
@@ -27,14 +34,16 @@ func New(stdout io.Writer, decls []ast.Decl) (*machine, error) {
 		switch v := decl.(type) {
 		case *ast.StructDecl:
 			if _, ok := mch.structs[v.Name.Value]; ok {
-				return nil, fmt.Errorf("%w: %s", ErrDuplicateStruct, v.Name.Value)
+				return nil, mch.errorAt(v.Name, fmt.Errorf("%w: %s", ErrDuplicateStruct, v.Name.Value))
 			}
 			mch.structs[v.Name.Value] = v
 		case *ast.FuncDecl:
 			if _, ok := mch.funcs[v.Name.Value]; ok {
-				return nil, fmt.Errorf("%w: %s", ErrDuplicateFunc, v.Name.Value)
+				return nil, mch.errorAt(v.Name, fmt.Errorf("%w: %s", ErrDuplicateFunc, v.Name.Value))
 			}
 			mch.funcs[v.Name.Value] = v
+		case *ast.VarDecl:
+			mch.vars = append(mch.vars, v)
 		}
 	}
 	return &mch, nil
@@ -48,6 +57,12 @@ func (m *machine) Run() error {
 	if len(main.Args) != 0 || main.ReturnType.Kind != ast.TVoid {
 		return ErrInvalidMain
 	}
+	m.globals = &scope{}
+	for _, decl := range m.vars {
+		if _, err := m.exec(m.globals, decl.Var); err != nil {
+			return err
+		}
+	}
 	_, err := m.call(main.Name, nil)
 	if err != nil {
 		return err
@@ -1,12 +1,17 @@
 package machine_test
 
 import (
+	"errors"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/nurtai325/qurtc/internal/machine"
 	"github.com/nurtai325/qurtc/internal/parser"
 	"github.com/nurtai325/qurtc/internal/testutils"
+	"github.com/nurtai325/qurtc/internal/types"
 )
 
 func TestMachine(t *testing.T) {
@@ -16,7 +21,7 @@ func TestMachine(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		program, err := machine.New(os.Stdout, decls)
+		program, err := machine.New(os.Stdout, contents, decls)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -26,3 +31,487 @@ func TestMachine(t *testing.T) {
 		}
 	})
 }
+
+func TestMachineRuntimeError(t *testing.T) {
+	src := `функция бүтін алу(т [3]бүтін, и бүтін) {
+    қайтар т[и];
+}
+
+функция ештеңе негізгі() {
+    айнымалы т [3]бүтін;
+    жаз(алу(т, 5));
+}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	program, err := machine.New(io.Discard, []byte(src), decls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = program.Run()
+	var rerr *machine.RuntimeError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected *machine.RuntimeError, got %v", err)
+	}
+	if !errors.Is(err, types.ErrOutOfBound) {
+		t.Errorf("expected %v, got %v", types.ErrOutOfBound, rerr.Err)
+	}
+	if rerr.Pos.Line != 2 || rerr.Pos.Col != 12 {
+		t.Errorf("expected error at 2:12, got %v", rerr.Pos)
+	}
+	if want := []string{"негізгі", "алу"}; !slices.Equal(rerr.Stack, want) {
+		t.Errorf("expected call stack %v, got %v", want, rerr.Stack)
+	}
+	if !strings.Contains(rerr.Error(), "қайтар т[и];") {
+		t.Errorf("expected error to contain the source line, got:\n%s", rerr.Error())
+	}
+}
+
+func run(t *testing.T, src string) (string, error) {
+	t.Helper()
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout strings.Builder
+	program, err := machine.New(&stdout, []byte(src), decls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = program.Run()
+	return stdout.String(), err
+}
+
+func TestMachineStrings(t *testing.T) {
+	src := "функция ештеңе негізгі() {\n" +
+		"    айнымалы т [2]бөлшек = {1.5, 2.0}\n" +
+		"    айнымалы аты жол = \"Әлем\"\n" +
+		"    жаз(\"Сәлем, {аты}!\\t{т[0] + т[1]} {т[0] > т[1]} \\{\\u{4D9}\\}\")\n" +
+		"    жаз(`бірінші\nекінші\\n`)\n" +
+		"}\n"
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Сәлем, Әлем!\t3.5 жоқ {ә}\nбірінші\nекінші\\n\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+}
+
+func TestMachineAssignOps(t *testing.T) {
+	src := `функция бүтін екі() {
+    жаз("екі");
+    қайтар 1;
+}
+
+функция ештеңе негізгі() {
+    айнымалы т [3]бүтін = {1, 2, 3};
+    т[екі()] *= (т[0] + 1) * 2;
+    (т)[0] += 1;
+    ((т[0]))++;
+    айнымалы с бөлшек = 1.5;
+    с++;
+    айнымалы ж жол = "а";
+    қайтала(айнымалы и бүтін = 0; и < 3; и++) {
+        ж += "б";
+    }
+    жаз(т, с, ж, -(т[2] - 5) % 2);
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "екі\n[3 8 3] 2.5 аббб 0\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+}
+
+func TestMachineLoops(t *testing.T) {
+	src := `функция бүтін табу(т []бүтін, ізделген бүтін) {
+    қайтала(і, х : т) {
+        егер(х == ізделген) {
+            қайтар і;
+        }
+    }
+    қайтар -1;
+}
+
+функция ештеңе негізгі() {
+    айнымалы т []бүтін = {5, 7, 9};
+    қайтала(х : т) {
+        қос(т, х);
+    }
+    айнымалы с сөздік[жол]бүтін = {"б": 1, "а": 2, "в": 3};
+    айнымалы ж жол = "";
+    қайтала(к, м : с) {
+        өшір(с, "а");
+        ж += "{к}{м}";
+    }
+    қайтала(ә : "сәлем") {
+        егер(ә == "ә") {
+            өткіз;
+        }
+        ж += ә;
+    }
+    айнымалы қосынды бүтін = 0;
+    қайтала(і : 1..5) {
+        қосынды += і;
+    }
+    қайтала(і : 5..1) {
+        қосынды = 0;
+    }
+    айнымалы н бүтін = 0;
+    қайтала(н < 10) {
+        н += 3;
+    }
+    қайтала {
+        н--;
+        егер(н == 5) {
+            тоқта;
+        }
+    }
+    жаз(ұзындық(т), ж, қосынды, н, табу(т, 9), табу(т, 1));
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "6 б1в3слем 10 5 2 -1\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+}
+
+func TestMachineSwitch(t *testing.T) {
+	src := `функция жол күн(н бүтін) {
+    таңда(н) {
+    әдепкі:
+        қайтар "жоқ";
+    жағдай 6, 7:
+        қайтар "демалыс";
+    жағдай 1, 2, 3, 4, 5:
+        қайтар "жұмыс";
+    }
+}
+
+функция ештеңе негізгі() {
+    айнымалы ж жол = "";
+    қайтала(і : 0..6) {
+        таңда(і % 3) {
+        жағдай 0:
+            өткіз;
+        жағдай 1:
+            ж += "а";
+            тоқта;
+            ж += "!";
+        }
+        ж += "б";
+    }
+    таңда("x") {
+    жағдай "y":
+        ж += "y";
+    }
+    жаз(күн(0), күн(6), күн(3), ж);
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "жоқ демалыс жұмыс аббабб\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+}
+
+func TestMachineLabels(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+    айнымалы ж жол = "";
+    сыртқы: қайтала(і : 0..3) {
+        қайтала(j : 0..3) {
+            егер(j > і) {
+                өткіз сыртқы;
+            }
+            егер(і == 2) {
+                тоқта сыртқы;
+            }
+            ж += "{і}{j} ";
+        }
+    }
+    айнымалы н бүтін = 0;
+    цикл: қайтала {
+        таңда(н) {
+        жағдай 3:
+            тоқта цикл;
+        }
+        егер(н < 5) {
+            н++;
+            өткіз;
+        }
+        ж += "!";
+    }
+    қайтала {
+        егер(иә) {
+            тоқта;
+        }
+        ж += "?";
+    }
+    жаз(ж, н);
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "00 10 11  3\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+
+	for _, tt := range []struct {
+		body string
+		err  error
+	}{
+		{"тоқта;", machine.ErrBreakInNotLoop},
+		{"өткіз;", machine.ErrContinueInNotLoop},
+		{"қайтала(і : 0..1) {\n        тоқта сыртқы;\n    }", machine.ErrUnknownLabel},
+	} {
+		src := "функция ештеңе негізгі() {\n    " + tt.body + "\n}\n"
+		if _, err := run(t, src); !errors.Is(err, tt.err) {
+			t.Errorf("%q: expected %v, got %v", tt.body, tt.err, err)
+		}
+	}
+}
+
+func TestMachineLists(t *testing.T) {
+	src := `құрылым түйін {
+    аты жол,
+    балалары []түйін,
+}
+
+функция ештеңе негізгі() {
+    айнымалы т []бүтін;
+    қос(т, 1, 2, 3);
+    қос(т, 4);
+    өшір(т, 0);
+    айнымалы б []бүтін = бөлік(т, 1, ұзындық(т));
+    б[0] = 10;
+    айнымалы ағаш түйін;
+    айнымалы бала түйін;
+    қос(ағаш.балалары, бала);
+    жаз(т, б, ұзындық(т), ұзындық("сәлем"), ұзындық(ағаш.балалары));
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[2 3 4] [10 4] 3 5 1\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+
+	_, err = run(t, `функция ештеңе негізгі() {
+    айнымалы т []бүтін = {1, 2};
+    жаз(бөлік(т, 1, 3));
+}
+`)
+	if !errors.Is(err, types.ErrSliceBounds) {
+		t.Errorf("expected %v, got %v", types.ErrSliceBounds, err)
+	}
+}
+
+func TestMachineMaps(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+    айнымалы с сөздік[жол]бүтін = {"б": 2, "а": 1};
+    с["в"] = 3;
+    с["б"] += 10;
+    с["а"]++;
+    өшір(с, "б");
+    өшір(с, "жоқ");
+    с["б"] = 0;
+    айнымалы бос сөздік[бүтін]шын;
+    жаз(с, ұзындық(с), бар(с, "а"), бар(с, "г"), бос, "{с["в"]}");
+}
+`
+	out, err := run(t, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{а: 2, в: 3, б: 0} 3 иә жоқ {} 3\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+
+	for _, src := range []string{
+		"функция ештеңе негізгі() {\n    айнымалы с сөздік[жол]бүтін;\n    жаз(с[\"а\"]);\n}\n",
+		"функция ештеңе негізгі() {\n    айнымалы с сөздік[жол]бүтін;\n    с[\"а\"]++;\n}\n",
+	} {
+		if _, err := run(t, src); !errors.Is(err, types.ErrNoKey) {
+			t.Errorf("expected %v, got %v", types.ErrNoKey, err)
+		}
+	}
+}
+
+func TestMachineConst(t *testing.T) {
+	_, err := run(t, `тұрақты т [2]бүтін = {1, 2};
+
+функция ештеңе негізгі() {
+    т[0] = 3;
+}
+`)
+	if !errors.Is(err, machine.ErrAssignToConst) {
+		t.Errorf("expected %v, got %v", machine.ErrAssignToConst, err)
+	}
+}
+
+func TestMachineReturn(t *testing.T) {
+	out, err := run(t, `функция ештеңе сәлем(а шын) {
+    егер (а) {
+        қайтар;
+    }
+    жаз("сәлем");
+}
+
+функция бүтін белгісіз(а шын) {
+    егер (а) {
+        қайтар 1;
+    }
+}
+
+функция ештеңе негізгі() {
+    сәлем(иә);
+    сәлем(жоқ);
+    жаз(белгісіз(иә));
+    жаз(белгісіз(жоқ));
+}
+`)
+	if want := "сәлем\n1\n"; out != want {
+		t.Errorf("expected output %q, got %q", want, out)
+	}
+	if !errors.Is(err, machine.ErrMissingReturn) {
+		t.Errorf("expected %v, got %v", machine.ErrMissingReturn, err)
+	}
+}
+
+var scopeTests = []struct {
+	name string
+	src  string
+	out  string
+}{
+	{
+		name: "assignment inside a branch changes the outer variable",
+		src: `функция ештеңе негізгі() {
+    айнымалы а бүтін = 1;
+    егер (а == 1) {
+        а = 2;
+    } әйтпесе {
+        а = 3;
+    }
+    жаз(а);
+    егер (а == 1) {
+        а = 4;
+    } әйтпесе егер (а == 2) {
+        а = 5;
+    } әйтпесе {
+        а = 6;
+    }
+    жаз(а);
+}`,
+		out: "2\n5\n",
+	},
+	{
+		name: "nested loops accumulate into outer variables",
+		src: `функция ештеңе негізгі() {
+    айнымалы қосынды бүтін = 0;
+    айнымалы жұптар бүтін = 0;
+    қайтала (айнымалы i бүтін = 0; i < 3; i = i + 1) {
+        қайтала (айнымалы j бүтін = 0; j < 4; j = j + 1) {
+            қосынды = қосынды + i * j;
+            егер (i % 2 == j % 2) {
+                жұптар = жұптар + 1;
+            }
+        }
+    }
+    жаз(қосынды);
+    жаз(жұптар);
+}`,
+		out: "18\n6\n",
+	},
+	{
+		name: "block variables do not leak and may shadow outer ones",
+		src: `функция ештеңе негізгі() {
+    айнымалы а бүтін = 1;
+    қайтала (айнымалы i бүтін = 0; i < 2; i = i + 1) {
+        айнымалы а бүтін = 10 + i;
+        айнымалы б бүтін = а;
+        жаз(б);
+    }
+    егер (иә) {
+        айнымалы а жол = "ішкі";
+        жаз(а);
+    }
+    жаз(а);
+}`,
+		out: "10\n11\nішкі\n1\n",
+	},
+	{
+		name: "loop variable changed in the body",
+		src: `функция ештеңе негізгі() {
+    айнымалы қадам бүтін = 0;
+    қайтала (айнымалы i бүтін = 0; i < 10; i = i + 1) {
+        егер (i == 2) {
+            i = 7;
+        }
+        қадам = қадам + 1;
+    }
+    жаз(қадам);
+}`,
+		out: "5\n",
+	},
+	{
+		name: "function scopes are separate",
+		src: `функция бүтін екіЕсе(а бүтін) {
+    а = а * 2;
+    қайтар а;
+}
+
+функция ештеңе негізгі() {
+    айнымалы а бүтін = 3;
+    жаз(екіЕсе(а));
+    жаз(а);
+}`,
+		out: "6\n3\n",
+	},
+	{
+		name: "globals are initialized in order and shared by functions",
+		src: `тұрақты қадам бүтін = 2;
+айнымалы санауыш бүтін = қадам * 10;
+
+функция ештеңе арттыр() {
+    санауыш = санауыш + қадам;
+}
+
+функция ештеңе негізгі() {
+    арттыр();
+    арттыр();
+    жаз(санауыш);
+    айнымалы қадам бүтін = 100;
+    жаз(қадам);
+}`,
+		out: "24\n100\n",
+	},
+}
+
+func TestMachineScopes(t *testing.T) {
+	for _, tt := range scopeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := run(t, tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != tt.out {
+				t.Errorf("expected output %q, got %q", tt.out, out)
+			}
+		})
+	}
+}
@@ -1,28 +1,28 @@
 package machine
 
 import (
-	"maps"
-	"fmt"
-
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/types"
 )
 
+// scope holds the variables declared in one block and links to the scope of
+// the enclosing block, so entering a block is cheap and assignments to outer
+// variables change them where they are declared.
 type scope struct {
-	parentVars map[string]types.Type
-	childVars  map[string]types.Type
-	isLoop     bool
-	isContinue bool
-	isBreak    bool
+	vars   map[string]types.Type
+	consts map[string]bool // names in vars declared with тұрақты
+	parent *scope
 }
 
-func newFuncScope(funcDecl *ast.FuncDecl, args []types.Type) (*scope, error) {
+// newFuncScope returns the scope of a call to funcDecl. Its parent is the
+// scope of the global variables.
+func newFuncScope(globals *scope, funcDecl *ast.FuncDecl, args []types.Type) (*scope, error) {
 	if len(funcDecl.Args) != len(args) {
 		return nil, ErrFuncArgMismatch
 	}
 	newScope := scope{
-		parentVars: make(map[string]types.Type),
-		childVars:  make(map[string]types.Type, len(funcDecl.Args)),
+		vars:   make(map[string]types.Type, len(funcDecl.Args)),
+		parent: globals,
 	}
 	for i, arg := range funcDecl.Args {
 		if !types.IsOfType(args[i], arg.Type) {
@@ -34,48 +34,62 @@ func newFuncScope(funcDecl *ast.FuncDecl, args []types.Type) (*scope, error) {
 }
 
 func (s *scope) add(name string, value types.Type) bool {
-	_, ok := s.childVars[name]
-	if ok {
-		fmt.Println("hello", name, value, s.childVars)
+	if _, ok := s.vars[name]; ok {
+		return false
+	}
+	if s.vars == nil {
+		s.vars = make(map[string]types.Type)
+	}
+	s.vars[name] = value
+	return true
+}
+
+func (s *scope) addConst(name string, value types.Type) bool {
+	if !s.add(name, value) {
 		return false
 	}
-	s.childVars[name] = value
+	if s.consts == nil {
+		s.consts = make(map[string]bool)
+	}
+	s.consts[name] = true
 	return true
 }
 
+// lookup returns the scope name is declared in, or nil.
+func (s *scope) lookup(name string) *scope {
+	for curr := s; curr != nil; curr = curr.parent {
+		if _, ok := curr.vars[name]; ok {
+			return curr
+		}
+	}
+	return nil
+}
+
 func (s *scope) get(name string) types.Type {
-	val, ok := s.childVars[name]
-	if !ok {
-		return s.parentVars[name]
+	if owner := s.lookup(name); owner != nil {
+		return owner.vars[name]
+	}
+	return nil
+}
+
+func (s *scope) isConst(name string) bool {
+	if owner := s.lookup(name); owner != nil {
+		return owner.consts[name]
 	}
-	return val
+	return false
 }
 
 func (s *scope) set(name string, value types.Type) bool {
-	_, ok := s.childVars[name]
-	if !ok {
-		_, ok := s.parentVars[name]
-		if !ok {
-			return false
-		}
-		s.parentVars[name] = value
-		return true
+	owner := s.lookup(name)
+	if owner == nil {
+		return false
 	}
-	s.childVars[name] = value
+	owner.vars[name] = value
 	return true
 }
 
 func (s *scope) newBlockScope() *scope {
 	return &scope{
-		parentVars: mergeScopes(s.parentVars, s.childVars),
-		childVars:  make(map[string]types.Type),
-		isLoop:     s.isLoop,
+		parent: s,
 	}
 }
-
-func mergeScopes(childScope, parentScope map[string]types.Type) map[string]types.Type {
-	merged := make(map[string]types.Type, len(childScope)+len(parentScope))
-	maps.Copy(merged, parentScope)
-	maps.Copy(merged, childScope)
-	return merged
-}
@@ -3,10 +3,28 @@ package machine
 import (
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/token"
 	"github.com/nurtai325/qurtc/internal/types"
 )
 
-func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (types.Type, error) {
+// jump is how a қайтар, a тоқта or an өткіз leaves the statements after it.
+// It is passed up through the blocks around it until the function, the loop
+// or the таңда it ends.
+type jump struct {
+	tok   token.Token // token.RETURN, token.BREAK or token.CONTINUE
+	label string      // the loop тоқта or өткіз names, "" for the innermost
+	val   types.Type  // the value of a қайтар, nil for a bare қайтар
+	stmt  ast.Stmt
+}
+
+// exec runs stmt. A non-nil result means that the statements after stmt must
+// be skipped, see jump.
+func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (_ *jump, err error) {
+	defer func() {
+		if err != nil {
+			err = m.errorAt(stmt, err)
+		}
+	}()
 	switch v := stmt.(type) {
 	case *ast.VarStmt:
 		var val types.Type
@@ -26,7 +44,11 @@ func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (types.Type, error) {
 			}
 			val = res
 		}
-		if !parentScope.add(v.Name.Value, val) {
+		add := parentScope.add
+		if v.Const {
+			add = parentScope.addConst
+		}
+		if !add(v.Name.Value, val) {
 			return nil, ErrVarExists
 		}
 		return nil, nil
@@ -35,52 +57,38 @@ func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (types.Type, error) {
 		if err != nil {
 			return nil, err
 		}
-		switch assignee := v.Var.(type) {
-		case *ast.NameExpr:
-			if !types.IsSameType(parentScope.get(assignee.Value), val) {
-				return nil, types.ErrNotSameType
-			}
-			if !parentScope.set(assignee.Value, val) {
-				return nil, types.ErrNotSameType
+		return nil, m.assign(parentScope, v.Var, func(old types.Type) (types.Type, error) {
+			if v.Op == token.ILLEGAL {
+				return val, nil
 			}
-			return nil, nil
-		case *ast.ArrayAccessExpr:
-			res, err := m.eval(parentScope, assignee.Array)
-			if err != nil {
-				return nil, err
-			}
-			arr, ok := res.(*types.Array)
-			if !ok {
-				return nil, ErrArrAccessOnNotArr
-			}
-			res, err = m.eval(parentScope, assignee.Index)
-			if err != nil {
-				return nil, err
+			if old == nil {
+				return nil, types.ErrNoKey
 			}
-			index, ok := res.(types.Int)
-			if !ok {
-				return nil, ErrArrAccessOnNotArr
+			return m.binary(v.Op, old, val)
+		})
+	case *ast.IncDecStmt:
+		return nil, m.assign(parentScope, v.Var, func(old types.Type) (types.Type, error) {
+			if old == nil {
+				return nil, types.ErrNoKey
 			}
-			return nil, arr.Set(int(index), val)
-		case *ast.SelectorExpr:
-			res, err := m.eval(parentScope, assignee.Struct)
-			if err != nil {
-				return nil, err
+			var one types.Type = types.Int(1)
+			if _, ok := old.(types.Float); ok {
+				one = types.Float(1)
 			}
-			structVal, ok := res.(*types.Struct)
-			if !ok {
-				return nil, ErrArrAccessOnNotArr
+			if v.Op == token.INC {
+				return m.binary(token.ADD, old, one)
 			}
-			return nil, structVal.Set(assignee.Field.Value, val)
-		default:
-			return nil, ErrInvalidAssign
-		}
+			return m.binary(token.SUB, old, one)
+		})
 	case *ast.ReturnStmt:
+		if v.Value == nil {
+			return &jump{tok: token.RETURN, stmt: v}, nil
+		}
 		res, err := m.eval(parentScope, v.Value)
 		if err != nil {
 			return nil, err
 		}
-		return res, nil
+		return &jump{tok: token.RETURN, val: res, stmt: v}, nil
 	case *ast.CallStmt:
 		args, err := m.evalAll(parentScope, v.CallExpr.Args)
 		if err != nil {
@@ -100,31 +108,172 @@ func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (types.Type, error) {
 		if !ok {
 			return nil, ErrIfWithNoBool
 		}
-		ifScope := parentScope.newBlockScope()
 		if cond == false {
 			if v.Else == nil {
 				return nil, nil
 			}
 			switch elseBlock := v.Else.(type) {
 			case ast.Stmts:
-				return m.execBlock(ifScope.newBlockScope(), v.Then)
+				return m.execBlock(parentScope.newBlockScope(), elseBlock)
 			case *ast.IfStmt:
-				return m.exec(ifScope, elseBlock)
+				return m.exec(parentScope, elseBlock)
 			default:
 				return nil, ErrInvalidElse
 			}
 		}
-		return m.execBlock(ifScope.newBlockScope(), v.Then)
+		return m.execBlock(parentScope.newBlockScope(), v.Then)
 	case *ast.ForStmt:
-		loopScope := parentScope.newBlockScope()
-		loopScope.isLoop = true
-		_, err := m.exec(loopScope, v.Init)
+		return m.forStmt(parentScope, v, "")
+	case *ast.ForEachStmt:
+		return m.forEachStmt(parentScope, v, "")
+	case *ast.LabeledStmt:
+		switch loop := v.Stmt.(type) {
+		case *ast.ForStmt:
+			return m.forStmt(parentScope, loop, v.Label.Value)
+		case *ast.ForEachStmt:
+			return m.forEachStmt(parentScope, loop, v.Label.Value)
+		default:
+			return nil, ErrInvalidFor
+		}
+	case *ast.SwitchStmt:
+		tag, err := m.eval(parentScope, v.Tag)
+		if err != nil {
+			return nil, err
+		}
+		// the әдепкі is run if no case matches, wherever it is written
+		var matched, dflt *ast.CaseClause
+	cases:
+		for _, clause := range v.Cases {
+			if clause.Values == nil {
+				dflt = clause
+			}
+			for _, val := range clause.Values {
+				res, err := m.eval(parentScope, val)
+				if err != nil {
+					return nil, err
+				}
+				if eq, err := m.eql(tag, res); err != nil {
+					return nil, err
+				} else if eq == types.Bool(true) {
+					matched = clause
+					break cases
+				}
+			}
+		}
+		if matched == nil {
+			matched = dflt
+		}
+		if matched == nil {
+			return nil, nil
+		}
+		j, err := m.execBlock(parentScope.newBlockScope(), matched.Body)
+		if j != nil && j.tok == token.BREAK && j.label == "" {
+			// тоқта without a label ends the таңда
+			return nil, nil
+		}
+		return j, err
+	case *ast.ContinueStmt:
+		return &jump{tok: token.CONTINUE, label: labelName(v.Label), stmt: v}, nil
+	case *ast.BreakStmt:
+		return &jump{tok: token.BREAK, label: labelName(v.Label), stmt: v}, nil
+	default:
+		return nil, parser.ErrUnknownStmt
+	}
+}
+
+// assign stores in target the value update returns for its old value, old
+// is nil for a new key of a map. The array and the index of target are
+// evaluated once, so а[f()] += 1 calls f once.
+func (m *machine) assign(assignScope *scope, target ast.Expr, update func(old types.Type) (types.Type, error)) error {
+	if name := ast.AssignedVar(target); name != nil && assignScope.isConst(name.Value) {
+		return ErrAssignToConst
+	}
+	switch assignee := ast.Unparen(target).(type) {
+	case *ast.NameExpr:
+		val, err := update(assignScope.get(assignee.Value))
+		if err != nil {
+			return err
+		}
+		if !types.IsSameType(assignScope.get(assignee.Value), val) {
+			return types.ErrNotSameType
+		}
+		if !assignScope.set(assignee.Value, val) {
+			return types.ErrNotSameType
+		}
+		return nil
+	case *ast.ArrayAccessExpr:
+		res, err := m.eval(assignScope, assignee.Array)
+		if err != nil {
+			return err
+		}
+		if mp, ok := res.(*types.Map); ok {
+			key, err := m.eval(assignScope, assignee.Index)
+			if err != nil {
+				return err
+			}
+			old, _ := mp.Get(key)
+			val, err := update(old)
+			if err != nil {
+				return err
+			}
+			return mp.Set(key, val)
+		}
+		arr, ok := res.(*types.Array)
+		if !ok {
+			return ErrArrAccessOnNotArr
+		}
+		res, err = m.eval(assignScope, assignee.Index)
+		if err != nil {
+			return err
+		}
+		index, ok := res.(types.Int)
+		if !ok {
+			return ErrArrAccessOnNotArr
+		}
+		old, err := arr.Get(int(index))
+		if err != nil {
+			return err
+		}
+		val, err := update(old)
+		if err != nil {
+			return err
+		}
+		return arr.Set(int(index), val)
+	case *ast.SelectorExpr:
+		res, err := m.eval(assignScope, assignee.Struct)
+		if err != nil {
+			return err
+		}
+		structVal, ok := res.(*types.Struct)
+		if !ok {
+			return ErrArrAccessOnNotArr
+		}
+		old, err := structVal.Get(assignee.Field.Value)
+		if err != nil {
+			return err
+		}
+		val, err := update(old)
+		if err != nil {
+			return err
+		}
+		return structVal.Set(assignee.Field.Value, val)
+	default:
+		return ErrInvalidAssign
+	}
+}
+
+func (m *machine) forStmt(parentScope *scope, loop *ast.ForStmt, label string) (*jump, error) {
+	loopScope := parentScope.newBlockScope()
+	if loop.Init != nil {
+		_, err := m.exec(loopScope, loop.Init)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		for {
-			res, err := m.eval(loopScope, v.Cond)
+	for {
+		if loop.Cond != nil {
+			res, err := m.eval(loopScope, loop.Cond)
 			if err != nil {
 				return nil, err
 			}
@@ -135,56 +284,148 @@ func (m *machine) exec(parentScope *scope, stmt ast.Stmt) (types.Type, error) {
 			if !cond {
 				break
 			}
+		}
 
-			iterScope := loopScope.newBlockScope()
-			iterScope.isLoop = true
-			retVal, err := m.execBlock(iterScope, v.Body)
-			if err != nil {
-				return nil, err
-			}
-			if retVal != nil {
-				return retVal, nil
-			}
-			if iterScope.isBreak {
-				break
-			}
+		j, done, err := m.iterate(loopScope.newBlockScope(), loop.Body, label)
+		if err != nil || done {
+			return j, err
+		}
 
-			_, err = m.exec(loopScope, v.Post)
+		if loop.Post != nil {
+			_, err = m.exec(loopScope, loop.Post)
 			if err != nil {
 				return nil, err
 			}
 		}
-		return nil, nil
-	case *ast.ContinueStmt:
-		if !parentScope.isLoop {
-			return nil, ErrContinueInNotLoop
+	}
+	return nil, nil
+}
+
+func (m *machine) forEachStmt(parentScope *scope, loop *ast.ForEachStmt, label string) (*jump, error) {
+	next, err := m.iterator(parentScope, loop)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		key, val, ok := next()
+		if !ok {
+			break
 		}
-		parentScope.isContinue = true
-		return nil, nil
-	case *ast.BreakStmt:
-		if !parentScope.isLoop {
-			return nil, ErrBreakInNotLoop
+		iterScope := parentScope.newBlockScope()
+		if loop.Key != nil {
+			iterScope.add(loop.Key.Value, key)
+		}
+		iterScope.add(loop.Value.Value, val)
+		j, done, err := m.iterate(iterScope, loop.Body, label)
+		if err != nil || done {
+			return j, err
 		}
-		parentScope.isBreak = true
-		return nil, nil
-	default:
-		return nil, parser.ErrUnknownStmt
 	}
+	return nil, nil
 }
 
-func (m *machine) execBlock(currScope *scope, block []ast.Stmt) (types.Type, error) {
-	for _, stmt := range block {
-		retVal, err := m.exec(currScope, stmt)
+// iterate runs the body of the loop labelled label once in iterScope and
+// reports whether the loop is done. The jump is the one that goes on out of
+// the loop: a қайтар, or a тоқта or an өткіз of an outer loop.
+func (m *machine) iterate(iterScope *scope, body []ast.Stmt, label string) (*jump, bool, error) {
+	j, err := m.execBlock(iterScope, body)
+	if err != nil {
+		return nil, true, err
+	}
+	if j == nil {
+		return nil, false, nil
+	}
+	if j.tok == token.RETURN || j.label != "" && j.label != label {
+		return j, true, nil
+	}
+	return nil, j.tok == token.BREAK, nil
+}
+
+// labelName returns the name of label, "" for nil.
+func labelName(label *ast.NameExpr) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
+}
+
+// iterator returns the function that gives the next key and value of the
+// array, string, map or range loop runs over, ok is false after the last one.
+// The elements of an array are the ones it had before the loop, the keys of a
+// map removed in the loop are skipped.
+func (m *machine) iterator(iterScope *scope, loop *ast.ForEachStmt) (next func() (key, val types.Type, ok bool), err error) {
+	if rng, ok := loop.X.(*ast.RangeExpr); ok {
+		bounds, err := m.evalAll(iterScope, []ast.Expr{rng.From, rng.To})
 		if err != nil {
 			return nil, err
 		}
-		if retVal != nil {
-			return retVal, nil
+		from, okFrom := bounds[0].(types.Int)
+		to, okTo := bounds[1].(types.Int)
+		if !okFrom || !okTo {
+			return nil, ErrInvalidFor
 		}
-		if currScope.isContinue {
-			break
+		return func() (types.Type, types.Type, bool) {
+			if from >= to {
+				return nil, nil, false
+			}
+			from++
+			return nil, from - 1, true
+		}, nil
+	}
+	res, err := m.eval(iterScope, loop.X)
+	if err != nil {
+		return nil, err
+	}
+	i := 0
+	switch v := res.(type) {
+	case *types.Array:
+		elems, err := v.Slice(0, v.Len())
+		if err != nil {
+			return nil, err
+		}
+		return func() (types.Type, types.Type, bool) {
+			elem, err := elems.Get(i)
+			if err != nil {
+				return nil, nil, false
+			}
+			i++
+			return types.Int(i - 1), elem, true
+		}, nil
+	case types.String:
+		chars := []rune(string(v))
+		return func() (types.Type, types.Type, bool) {
+			if i >= len(chars) {
+				return nil, nil, false
+			}
+			i++
+			return types.Int(i - 1), types.String(chars[i-1]), true
+		}, nil
+	case *types.Map:
+		keys := v.Keys()
+		return func() (types.Type, types.Type, bool) {
+			for ; i < len(keys); i++ {
+				if val, err := v.Get(keys[i]); err == nil {
+					i++
+					if loop.Key == nil {
+						return nil, keys[i-1], true
+					}
+					return keys[i-1], val, true
+				}
+			}
+			return nil, nil, false
+		}, nil
+	}
+	return nil, ErrInvalidFor
+}
+
+// execBlock runs block in currScope up to the end or the first statement
+// that jumps.
+func (m *machine) execBlock(currScope *scope, block []ast.Stmt) (*jump, error) {
+	for _, stmt := range block {
+		j, err := m.exec(currScope, stmt)
+		if err != nil || j != nil {
+			return j, err
 		}
 	}
-	// return nil because func body didn't return anything
 	return nil, nil
 }
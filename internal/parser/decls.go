@@ -6,6 +6,7 @@ import (
 )
 
 func (p *parser) funcDecl() (ast.Decl, error) {
+	start := p.s.Pos()
 	typ, err := p.typ()
 	if err != nil {
 		return nil, err
@@ -41,15 +42,18 @@ func (p *parser) funcDecl() (ast.Decl, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ast.FuncDecl{
+	decl := &ast.FuncDecl{
 		Name:       name,
 		Args:       args,
 		ReturnType: typ,
 		Body:       body,
-	}, nil
+	}
+	decl.Span = p.span(start)
+	return decl, nil
 }
 
 func (p *parser) structDecl() (ast.Decl, error) {
+	start := p.s.Pos()
 	name, err := p.name()
 	if err != nil {
 		return nil, err
@@ -77,10 +81,24 @@ func (p *parser) structDecl() (ast.Decl, error) {
 			Type: typ,
 		})
 	}
-	return &ast.StructDecl{
+	decl := &ast.StructDecl{
 		Name:   name,
 		Fields: fields,
-	}, nil
+	}
+	decl.Span = p.span(start)
+	return decl, nil
+}
+
+func (p *parser) varDecl() (ast.Decl, error) {
+	stmt, err := p.varStmt(p.s.Pos(), p.s.Tok() == token.CONST)
+	if err != nil {
+		return nil, err
+	}
+	decl := &ast.VarDecl{
+		Var: stmt,
+	}
+	decl.Span = stmt.Span
+	return decl, nil
 }
 
 func (p *parser) fieldOrArg(end token.Token) (string, *ast.Type, error) {
@@ -96,6 +114,15 @@ func (p *parser) fieldOrArg(end token.Token) (string, *ast.Type, error) {
 	if err != nil {
 		return "", nil, err
 	}
+	if tok == token.SEMICOLON {
+		// a newline after the last field or argument ends the line with a ';'
+		if next, _ := p.s.PeekN(2); next == end {
+			p.expect(token.SEMICOLON)
+			if p.s.Lit() == "\n" {
+				return name.Value, typ, nil
+			}
+		}
+	}
 	if tok == token.COMMA {
 		p.expect(token.COMMA)
 		return name.Value, typ, nil
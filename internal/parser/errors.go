@@ -5,41 +5,131 @@ import (
 	"fmt"
 
 	"github.com/nurtai325/qurtc/internal/help"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/token"
 )
 
 var (
 	ErrUnexpectedEOF = errors.New("файл күтпеген жерден аяқталады")
 
-	ErrUnknownDecl       = errors.New("функция сыртында тек жаңа айнымалы, функция, құрылым жариялауға ғана болады")
+	ErrUnknownDecl       = errors.New("функция сыртында тек жаңа айнымалы, тұрақты, функция, құрылым жариялауға ғана болады")
 	ErrInvalidFuncDecl   = errors.New("функция жариялаудың ережелері сақталмаған")
 	ErrInvalidStructDecl = errors.New("құрылым жариялаудың ережелері сақталмаған")
 	ErrInvalidVarDecl    = errors.New("айнымалы жариялаудың ережелері сақталмаған")
+	ErrConstWithoutValue = errors.New("тұрақтыға жариялаған кезде мән беру керек, кейін оны өзгертуге болмайды")
 	ErrInvalidFieldOrArg = errors.New("ережеге сай емес аргумент немесе құрылым мүшесі")
 
-	ErrUnknownStmt = errors.New("бұндай оператор немесе нұсқау жоқ")
+	ErrUnknownStmt    = errors.New("бұндай оператор немесе нұсқау жоқ")
+	ErrInvalidSwitch  = errors.New("таңда ережесі сақталмаған, ол таңда (мән) { жағдай 1, 2: ... әдепкі: ... } болып жазылады")
+	ErrInvalidLabel   = errors.New("белгі тек қайтала алдында жазылады, мысалы сыртқы: қайтала (...) { }")
+	ErrInvalidForEach = errors.New("қайталау ережесі сақталмаған, ол қайтала (элемент : тізім) немесе қайтала (индекс, элемент : тізім) болып жазылады")
 
 	ErrInvalidExpr     = errors.New("ережеге сай емес өрнек табылмады")
 	ErrInvalidFuncCall = errors.New("функция шақыру ережесі сақталмаған")
 
 	ErrInvalidIdent  = errors.New("функция, айнымалы, тип атаулары ережеге сай есім болуы керек")
 	ErrInvalidArray  = errors.New("ережеге сай емес массив")
+	ErrInvalidMap    = errors.New("ережеге сай емес сөздік, ол {кілт: мән, кілт: мән} болып жазылады")
 	ErrInvalidString = errors.New("ережеге сай емес ЖОЛ")
 	ErrInvalidInt    = errors.New("ережеге сай емес БҮТІН")
 	ErrInvalidFloat  = errors.New("ережеге сай емес БӨЛШЕК")
+	ErrIntOverflow   = errors.New("бүтін сан тым үлкен")
+	ErrFloatRange    = errors.New("бөлшек сан тым үлкен немесе нөлге тым жақын")
 	ErrInvalidBool   = errors.New("ережеге сай емес ШЫН")
 
 	ErrInvalidArrayLen = errors.New("тізім ұзындығы 0 бола алмайды және тек БҮТІН сан ғана бола алады және [] арасында болу керек")
 	ErrInvalidTypeName = errors.New("айнымалы немесе функция аргументі типі ережеге сай есім болу керек")
+	ErrInvalidMapType  = errors.New("сөздік типі сөздік[кілт типі]мән типі болып жазылады, мысалы сөздік[жол]бүтін")
 )
 
-func (p *parser) errorAt(err error, helpPage help.DocPage) error {
-	// TODO: add source context line
-	errTempl := "Синтаксис қатесі (файл: %s, жол: %d, қатар: %d): %s\n"
-	if helpPage != "" {
-		errTempl += fmt.Sprintf("Мына сілтеме сізге қатеңізді түзеуге көмектесуі мүмкін: %s\n", helpPage)
-	} else {
-		errTempl += fmt.Sprintf("Синтаксис және тілдің ережелері туралы толық ақпарат: %s\n", help.SyntaxPage)
+// errSync is returned by a block that reached the next declaration while
+// skipping a broken statement. The statement error is already reported.
+var errSync = errors.New("синтаксис қатесінен кейін келесі жарияламаға өту")
+
+type (
+	// Error is a syntax error at a position in the source.
+	Error = scanner.Error
+	// ErrorList is a list of syntax errors in the order they appear in the file.
+	ErrorList = scanner.ErrorList
+)
+
+func (p *parser) errorAt(err error, helpPage help.DocPage) {
+	if tok, _ := p.s.Peek(); tok == token.ILLEGAL {
+		// the error came from peeking at an invalid token, point to it
+		p.s.Scan()
+	}
+	pos, end := p.s.Pos(), p.s.End()
+	// one error per line is enough, the rest are usually caused by the first
+	if n := len(p.errs); n > 0 && p.errs[n-1].Pos.Line == pos.Line {
+		return
+	}
+	p.errs = append(p.errs, &Error{
+		Kind:    "Синтаксис қатесі",
+		Pos:     pos,
+		End:     end,
+		Err:     err,
+		Snippet: p.s.Snippet(pos, end, 1),
+		Help:    fmt.Sprintf("Мына сілтеме сізге қатеңізді түзеуге көмектесуі мүмкін: %s", helpPage),
+	})
+}
+
+// syncDecl skips tokens until the start of the next declaration. Variable
+// declarations only start one outside of braces, so that the variables of a
+// skipped function body are skipped too.
+func (p *parser) syncDecl() {
+	depth := 0
+	for {
+		tok, _ := p.s.Peek()
+		switch tok {
+		case token.EOF, token.FUNC, token.STRUCT:
+			return
+		case token.VAR, token.CONST:
+			if depth == 0 {
+				return
+			}
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			depth = max(depth-1, 0)
+		}
+		p.skip()
+	}
+}
+
+// syncStmt skips tokens until the end of the current statement. It returns
+// false if the next declaration or the end of the file is reached first.
+func (p *parser) syncStmt() bool {
+	depth := 0
+	for {
+		tok, _ := p.s.Peek()
+		switch tok {
+		case token.EOF, token.FUNC, token.STRUCT:
+			return false
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			if depth == 0 {
+				// closes the enclosing block, leave it to the block
+				return true
+			}
+			depth--
+			if depth == 0 {
+				p.skip()
+				return true
+			}
+		case token.SEMICOLON:
+			if depth == 0 {
+				p.skip()
+				return true
+			}
+		}
+		p.skip()
+	}
+}
+
+// skip consumes one token, reporting it if it is illegal.
+func (p *parser) skip() {
+	if !p.s.Scan() && p.s.Tok() == token.ILLEGAL {
+		p.errorAt(p.s.Err(), help.QurtTour)
 	}
-	pos := p.s.Pos()
-	return fmt.Errorf(errTempl, pos.File(), pos.Line(), pos.Col(), err.Error())
 }
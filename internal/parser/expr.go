@@ -2,7 +2,10 @@ package parser
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/token"
@@ -18,6 +21,7 @@ const (
 	precAdd
 	precMul
 	precUnary
+	precPostfix
 )
 
 var precs = map[token.Token]precedence{
@@ -38,6 +42,11 @@ var precs = map[token.Token]precedence{
 	token.LAND: precAndAnd,
 
 	token.LOR: precOrOr,
+
+	// calls, selectors and indexing can follow any expression
+	token.LPAREN: precPostfix,
+	token.PERIOD: precPostfix,
+	token.LBRACK: precPostfix,
 }
 
 func (p *parser) expr(prec precedence) (ast.Expr, error) {
@@ -79,15 +88,17 @@ func (p *parser) prefix() (ast.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	op := p.s.Tok()
+	op, start := p.s.Tok(), p.s.Pos()
 	operand, err := p.expr(precUnary)
 	if err != nil {
 		return nil, err
 	}
-	return &ast.UnaryOpExpr{
+	expr := &ast.UnaryOpExpr{
 		Operand: operand,
 		Op:      op,
-	}, nil
+	}
+	expr.Span = p.span(start)
+	return expr, nil
 }
 
 func (p *parser) infix(left ast.Expr) (ast.Expr, error) {
@@ -107,6 +118,7 @@ func (p *parser) infix(left ast.Expr) (ast.Expr, error) {
 		return nil, err
 	}
 	expr.Right = right
+	expr.Span = p.span(left.Pos())
 	return &expr, nil
 }
 
@@ -118,36 +130,40 @@ func (p *parser) prec(op token.Token) precedence {
 }
 
 func (p *parser) nameExpr() (ast.Expr, error) {
-	var expr ast.Expr
-	name, err := p.name()
+	return p.name()
+}
+
+// paren parses an expression in parentheses.
+func (p *parser) paren() (ast.Expr, error) {
+	if _, err := p.expect(token.LPAREN); err != nil {
+		return nil, err
+	}
+	start := p.s.Pos()
+	x, err := p.expr(0)
 	if err != nil {
 		return nil, err
 	}
-	expr = name
-	for {
-		tok, err := p.peek()
-		if err != nil {
-			return nil, err
-		}
-		switch tok {
-		case token.LPAREN:
-			expr, err = p.call(expr)
-			if err != nil {
-				return nil, err
-			}
-		case token.PERIOD:
-			expr, err = p.selector(expr)
-			if err != nil {
-				return nil, err
-			}
-		case token.LBRACK:
-			expr, err = p.arrayAccess(expr)
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return expr, nil
-		}
+	if _, err := p.expect(token.RPAREN); err != nil {
+		return nil, err
+	}
+	expr := &ast.ParenExpr{X: x}
+	expr.Span = p.span(start)
+	return expr, nil
+}
+
+// postfix parses a call, a selector or an index after left.
+func (p *parser) postfix(left ast.Expr) (ast.Expr, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case token.LPAREN:
+		return p.call(left)
+	case token.PERIOD:
+		return p.selector(left)
+	default:
+		return p.arrayAccess(left)
 	}
 }
 
@@ -160,40 +176,29 @@ func (p *parser) call(caller ast.Expr) (*ast.CallExpr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidFuncCall, err)
 	}
-	return &ast.CallExpr{
+	expr := &ast.CallExpr{
 		Func: caller,
 		Args: args,
-	}, nil
+	}
+	expr.Span = p.span(caller.Pos())
+	return expr, nil
 }
 
 func (p *parser) selector(obj ast.Expr) (*ast.SelectorExpr, error) {
-	selectorExpr := &ast.SelectorExpr{
-		Struct: obj,
+	_, err := p.expect(token.PERIOD)
+	if err != nil {
+		return nil, err
 	}
-	for {
-		_, err := p.expect(token.PERIOD)
-		if err != nil {
-			return nil, err
-		}
-
-		name, err := p.name()
-		if err != nil {
-			return nil, err
-		}
-		selectorExpr = &ast.SelectorExpr{
-			Struct: selectorExpr,
-			Field:  name,
-		}
-
-		tok, err := p.peek()
-		if err != nil {
-			return nil, err
-		}
-		if tok != token.PERIOD {
-			break
-		}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
 	}
-	return selectorExpr, nil
+	expr := &ast.SelectorExpr{
+		Struct: obj,
+		Field:  name,
+	}
+	expr.Span = p.span(obj.Pos())
+	return expr, nil
 }
 
 func (p *parser) arrayAccess(arr ast.Expr) (*ast.ArrayAccessExpr, error) {
@@ -209,24 +214,83 @@ func (p *parser) arrayAccess(arr ast.Expr) (*ast.ArrayAccessExpr, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ast.ArrayAccessExpr{
+	expr := &ast.ArrayAccessExpr{
 		Array: arr,
 		Index: index,
-	}, nil
+	}
+	expr.Span = p.span(arr.Pos())
+	return expr, nil
 }
 
+// array parses an array literal, or a map literal if the first element is
+// followed by ':'.
 func (p *parser) array() (ast.Expr, error) {
 	_, err := p.expect(token.LBRACE)
 	if err != nil {
 		return nil, errors.Join(ErrInvalidArray, err)
 	}
-	elements, err := p.exprList(token.RBRACE)
-	if err != nil {
-		return nil, errors.Join(ErrInvalidArray, err)
+	start := p.s.Pos()
+	var elements []ast.Expr
+	if tok, _ := p.peek(); tok == token.RBRACE {
+		p.expect(token.RBRACE)
+	} else {
+		first, err := p.expr(0)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidArray, err)
+		}
+		if tok, _ := p.peek(); tok == token.COLON {
+			return p.mapLit(start, first)
+		}
+		elements = append(elements, first)
+		if tok, _ := p.peek(); tok == token.COMMA {
+			p.expect(token.COMMA)
+			rest, err := p.exprList(token.RBRACE)
+			if err != nil {
+				return nil, errors.Join(ErrInvalidArray, err)
+			}
+			elements = append(elements, rest...)
+		} else if _, err := p.expect(token.RBRACE); err != nil {
+			return nil, errors.Join(ErrInvalidArray, err)
+		}
 	}
-	return &ast.ArrayExpr{
+	expr := &ast.ArrayExpr{
 		Elements: elements,
-	}, nil
+	}
+	expr.Span = p.span(start)
+	return expr, nil
+}
+
+// mapLit parses the rest of a map literal starting at start after its
+// first key.
+func (p *parser) mapLit(start token.Pos, key ast.Expr) (ast.Expr, error) {
+	expr := &ast.MapExpr{}
+	for {
+		if _, err := p.expect(token.COLON); err != nil {
+			return nil, errors.Join(ErrInvalidMap, err)
+		}
+		value, err := p.expr(0)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidMap, err)
+		}
+		expr.Keys = append(expr.Keys, key)
+		expr.Values = append(expr.Values, value)
+
+		if tok, _ := p.peek(); tok != token.COMMA {
+			break
+		}
+		p.expect(token.COMMA)
+		if tok, _ := p.peek(); tok == token.RBRACE {
+			break
+		}
+		if key, err = p.expr(0); err != nil {
+			return nil, errors.Join(ErrInvalidMap, err)
+		}
+	}
+	if _, err := p.expect(token.RBRACE); err != nil {
+		return nil, errors.Join(ErrInvalidMap, err)
+	}
+	expr.Span = p.span(start)
+	return expr, nil
 }
 
 func (p *parser) string() (ast.Expr, error) {
@@ -234,7 +298,41 @@ func (p *parser) string() (ast.Expr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidString, err)
 	}
-	return &ast.StringExpr{Value: lit}, nil
+	expr := &ast.StringExpr{Value: lit}
+	expr.Span = p.span(p.s.Pos())
+	return expr, nil
+}
+
+// interp parses an interpolated string. The scanner returns its text parts
+// as INTERP_BEG, INTERP_MID and INTERP_END, the expressions are in between.
+func (p *parser) interp() (ast.Expr, error) {
+	lit, err := p.expect(token.INTERP_BEG)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidString, err)
+	}
+	expr := &ast.InterpExpr{}
+	start := p.s.Pos()
+	for {
+		if lit != "" {
+			text := &ast.StringExpr{Value: lit}
+			text.Span = p.span(p.s.Pos())
+			expr.Parts = append(expr.Parts, text)
+		}
+		if p.s.Tok() == token.INTERP_END {
+			break
+		}
+		part, err := p.expr(0)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidString, err)
+		}
+		expr.Parts = append(expr.Parts, part)
+		lit, err = p.expect(token.INTERP_MID, token.INTERP_END)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidString, err)
+		}
+	}
+	expr.Span = p.span(start)
+	return expr, nil
 }
 
 func (p *parser) int() (ast.Expr, error) {
@@ -242,11 +340,13 @@ func (p *parser) int() (ast.Expr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidInt, err)
 	}
-	val, err := strconv.ParseInt(lit, 10, 0)
+	val, err := parseInt(lit)
 	if err != nil {
 		return nil, errors.Join(ErrInvalidInt, err)
 	}
-	return &ast.IntExpr{Value: int(val)}, nil
+	expr := &ast.IntExpr{Value: int(val)}
+	expr.Span = p.span(p.s.Pos())
+	return expr, nil
 }
 
 func (p *parser) float() (ast.Expr, error) {
@@ -254,11 +354,48 @@ func (p *parser) float() (ast.Expr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidFloat, err)
 	}
-	val, err := strconv.ParseFloat(lit, 32)
+	val, err := parseFloat(lit)
 	if err != nil {
 		return nil, errors.Join(ErrInvalidFloat, err)
 	}
-	return &ast.FloatExpr{Value: float32(val)}, nil
+	expr := &ast.FloatExpr{Value: float32(val)}
+	expr.Span = p.span(p.s.Pos())
+	return expr, nil
+}
+
+// parseInt converts an INT literal as the scanner returns it. A leading 0
+// does not make it octal, that needs the 0o prefix.
+func parseInt(lit string) (int64, error) {
+	digits, base := strings.ReplaceAll(lit, "_", ""), 10
+	if len(digits) > 1 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 10 {
+			digits = digits[2:]
+		}
+	}
+	val, err := strconv.ParseInt(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("%w: %s, ең үлкен бүтін сан %d", ErrIntOverflow, lit, int64(math.MaxInt64))
+	}
+	return val, err
+}
+
+// parseFloat converts a FLOAT literal as the scanner returns it. Values that
+// do not fit in a бөлшек are errors, including small ones that would be 0.
+func parseFloat(lit string) (float64, error) {
+	val, err := strconv.ParseFloat(strings.ReplaceAll(lit, "_", ""), 32)
+	mantissa, _, _ := strings.Cut(strings.ToLower(lit), "e")
+	if errors.Is(err, strconv.ErrRange) || val == 0 && strings.ContainsAny(mantissa, "123456789") {
+		return 0, fmt.Errorf("%w: %s, бөлшек санның модулі %g мен %g аралығында болуы керек", ErrFloatRange, lit, math.SmallestNonzeroFloat32, math.MaxFloat32)
+	}
+	return val, err
 }
 
 func (p *parser) bool() (ast.Expr, error) {
@@ -266,22 +403,16 @@ func (p *parser) bool() (ast.Expr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidBool, err)
 	}
-	switch tok {
-	case token.TRUE:
-		_, err := p.expect(token.TRUE)
-		if err != nil {
-			return nil, errors.Join(ErrInvalidBool, err)
-		}
-		return &ast.BoolExpr{Value: true}, nil
-	case token.FALSE:
-		_, err := p.expect(token.FALSE)
-		if err != nil {
-			return nil, errors.Join(ErrInvalidBool, err)
-		}
-		return &ast.BoolExpr{Value: false}, nil
-	default:
+	if tok != token.TRUE && tok != token.FALSE {
+		return nil, errors.Join(ErrInvalidBool, err)
+	}
+	_, err = p.expect(tok)
+	if err != nil {
 		return nil, errors.Join(ErrInvalidBool, err)
 	}
+	expr := &ast.BoolExpr{Value: tok == token.TRUE}
+	expr.Span = p.span(p.s.Pos())
+	return expr, nil
 }
 
 func (p *parser) exprList(end token.Token) ([]ast.Expr, error) {
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"slices"
-	"strconv"
 
 	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/help"
@@ -14,65 +13,81 @@ import (
 
 type parser struct {
 	s           scanner.Scanner
+	errs        ErrorList
 	prefixFuncs map[token.Token]func() (ast.Expr, error)
 	infixFuncs  map[token.Token]func(left ast.Expr) (ast.Expr, error)
 }
 
 func New(filename string, input []byte) *parser {
 	newParser := &parser{
-		s: scanner.New(filename, []byte(input)),
+		s: scanner.New(filename, []byte(input), 0),
 	}
 	newParser.prefixFuncs = map[token.Token]func() (ast.Expr, error){
-		token.IDENT: newParser.nameExpr,
+		token.IDENT:  newParser.nameExpr,
+		token.LPAREN: newParser.paren,
 
 		token.LBRACE: newParser.array,
 
-		token.STRING: newParser.string,
-		token.INT:    newParser.int,
-		token.FLOAT:  newParser.float,
-		token.TRUE:   newParser.bool,
-		token.FALSE:  newParser.bool,
+		token.STRING:     newParser.string,
+		token.INTERP_BEG: newParser.interp,
+		token.INT:        newParser.int,
+		token.FLOAT:      newParser.float,
+		token.TRUE:       newParser.bool,
+		token.FALSE:      newParser.bool,
 
 		token.SUB: newParser.prefix,
 		token.NOT: newParser.prefix,
 	}
 	newParser.infixFuncs = make(map[token.Token]func(left ast.Expr) (ast.Expr, error))
-	for op := range precs {
-		newParser.infixFuncs[op] = newParser.infix
+	for op, prec := range precs {
+		if prec == precPostfix {
+			newParser.infixFuncs[op] = newParser.postfix
+		} else {
+			newParser.infixFuncs[op] = newParser.infix
+		}
 	}
 	return newParser
 }
 
-func (p *parser) Parse() (decls []ast.Decl, err error) {
-	// TODO: add known errors, return them from functions without panicking
-	for p.s.Scan() {
+// Parse parses the whole file. Parsing does not stop at the first syntax
+// error: the parser skips to the next declaration or statement and goes on,
+// so the returned error is an ErrorList of every error found.
+func (p *parser) Parse() ([]ast.Decl, error) {
+	var decls []ast.Decl
+	for {
+		p.s.Scan()
 		switch p.s.Tok() {
+		case token.EOF:
+			return decls, p.errs.Err()
+		case token.SEMICOLON:
+			// empty declaration, usually inserted after a closing brace
 		case token.FUNC:
-			decls, err = p.appendDecl(decls, p.funcDecl)
-			if err != nil {
-				return nil, p.errorAt(errors.Join(ErrInvalidFuncDecl, err), help.FunctionsPage)
-			}
+			decls = p.appendDecl(decls, p.funcDecl, ErrInvalidFuncDecl, help.FunctionsPage)
 		case token.STRUCT:
-			decls, err = p.appendDecl(decls, p.structDecl)
-			if err != nil {
-				return nil, p.errorAt(errors.Join(ErrInvalidStructDecl, err), help.StructsPage)
-			}
+			decls = p.appendDecl(decls, p.structDecl, ErrInvalidStructDecl, help.StructsPage)
+		case token.VAR, token.CONST:
+			decls = p.appendDecl(decls, p.varDecl, ErrInvalidVarDecl, help.VarsPage)
+		case token.ILLEGAL:
+			p.errorAt(p.s.Err(), help.QurtTour)
+			p.syncDecl()
 		default:
-			return nil, p.errorAt(ErrUnknownDecl, help.SyntaxPage)
+			p.errorAt(ErrUnknownDecl, help.SyntaxPage)
+			p.syncDecl()
 		}
 	}
-	if p.s.Tok() == token.ILLEGAL {
-		return nil, p.errorAt(p.s.Err(), help.QurtTour)
-	}
-	return decls, nil
 }
 
-func (p *parser) appendDecl(decls []ast.Decl, fn func() (ast.Decl, error)) ([]ast.Decl, error) {
+func (p *parser) appendDecl(decls []ast.Decl, fn func() (ast.Decl, error), declErr error, helpPage help.DocPage) []ast.Decl {
 	decl, err := fn()
 	if err != nil {
-		return nil, err
+		// errSync means the error is already reported by a block
+		if !errors.Is(err, errSync) {
+			p.errorAt(errors.Join(declErr, err), helpPage)
+		}
+		p.syncDecl()
+		return decls
 	}
-	return append(decls, decl), nil
+	return append(decls, decl)
 }
 
 func (p *parser) name() (*ast.NameExpr, error) {
@@ -80,33 +95,66 @@ func (p *parser) name() (*ast.NameExpr, error) {
 	if err != nil {
 		return nil, errors.Join(ErrInvalidIdent, err)
 	}
-	return &ast.NameExpr{Value: lit}, nil
+	name := &ast.NameExpr{Value: lit}
+	name.Span = p.span(p.s.Pos())
+	return name, nil
 }
 
 func (p *parser) typ() (*ast.Type, error) {
 	var t ast.Type
+	var start token.Pos
 	if tok, _ := p.peek(); tok == token.LBRACK {
-		arrLen, err := p.arrlen()
-		if err != nil {
-			return nil, errors.Join(ErrInvalidArrayLen, err)
-		}
+		p.expect(token.LBRACK)
+		start = p.s.Pos()
 		t.IsArray = true
-		t.ArrayLen = arrLen
+		if tok, _ := p.peek(); tok == token.RBRACK {
+			// a list, the length is left 0
+			p.expect(token.RBRACK)
+		} else {
+			arrLen, err := p.arrlen()
+			if err != nil || arrLen == 0 {
+				return nil, errors.Join(ErrInvalidArrayLen, err)
+			}
+			t.ArrayLen = arrLen
+		}
 	}
 	name, err := p.name()
 	if err != nil {
 		return nil, errors.Join(ErrInvalidTypeName, err)
 	}
 	t.Name = name
-	t.Kind = ast.GetKind(name.Value)
+	t.Kind = ast.GetKind(name.Value, p.s.Dialect())
+	if t.Kind == ast.TMap {
+		if t.Key, t.Value, err = p.mapTypes(); err != nil {
+			return nil, errors.Join(ErrInvalidMapType, err)
+		}
+	}
+	if !start.IsValid() {
+		start = name.Pos()
+	}
+	t.Span = p.span(start)
 	return &t, nil
 }
 
-func (p *parser) arrlen() (int, error) {
-	_, err := p.expect(token.LBRACK)
-	if err != nil {
-		return 0, err
+// mapTypes parses the [key]value types of a map after its name.
+func (p *parser) mapTypes() (key, value *ast.Type, err error) {
+	if _, err := p.expect(token.LBRACK); err != nil {
+		return nil, nil, err
+	}
+	if key, err = p.typ(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := p.expect(token.RBRACK); err != nil {
+		return nil, nil, err
+	}
+	if value, err = p.typ(); err != nil {
+		return nil, nil, err
 	}
+	return key, value, nil
+}
+
+// arrlen parses the length of an array type after the opening '['.
+func (p *parser) arrlen() (int, error) {
 	lit, err := p.expect(token.INT)
 	if err != nil {
 		return 0, err
@@ -114,7 +162,7 @@ func (p *parser) arrlen() (int, error) {
 	if _, err := p.expect(token.RBRACK); err != nil {
 		return 0, err
 	}
-	arrayLen, err := strconv.ParseInt(lit, 10, 0)
+	arrayLen, err := parseInt(lit)
 	if err != nil {
 		return 0, err
 	}
@@ -129,11 +177,20 @@ func (p *parser) expect(toks ...token.Token) (string, error) {
 			return "", p.s.Err()
 		}
 	} else if !slices.Contains(toks, p.s.Tok()) {
-		return "", fmt.Errorf("күтпеген таңба немесе cөз: %v керек, бірақ %q табылды", toks, p.s.Tok().String())
+		found := fmt.Sprintf("%q", p.s.Tok().String())
+		if p.s.Tok() == token.SEMICOLON && p.s.Lit() == "\n" {
+			found = "жол соңы"
+		}
+		return "", fmt.Errorf("күтпеген таңба немесе cөз: %v керек, бірақ %s табылды", toks, found)
 	}
 	return p.s.Lit(), nil
 }
 
+// span returns the range from start to the end of the last scanned token.
+func (p *parser) span(start token.Pos) ast.Span {
+	return ast.Span{StartPos: start, EndPos: p.s.End()}
+}
+
 func (p *parser) peek() (token.Token, error) {
 	tok, err := p.s.Peek()
 	if tok == token.EOF {
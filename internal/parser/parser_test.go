@@ -1,10 +1,14 @@
 package parser_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/scanner"
 	"github.com/nurtai325/qurtc/internal/testutils"
+	"github.com/nurtai325/qurtc/internal/token"
 )
 
 func TestParser(t *testing.T) {
@@ -16,3 +20,393 @@ func TestParser(t *testing.T) {
 		}
 	})
 }
+
+func TestParserErrors(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+    айнымалы а бүтін = ;
+    айнымалы б бүтін = 5
+    жаз(а);
+    #
+}
+
+функция бүтін екі( {
+    қайтар 2;
+}
+
+құрылым адам {
+    аты жол
+    жасы бүтін,
+}
+
+тұрақты ж бүтін;
+`
+	_, err := parser.New("test.құрт", []byte(src)).Parse()
+	var errs parser.ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected parser.ErrorList, got %v", err)
+	}
+	wantLines := []int{2, 5, 8, 13, 17}
+	if len(errs) != len(wantLines) {
+		t.Fatalf("expected %d errors, got %d:\n%v", len(wantLines), len(errs), err)
+	}
+	for i, line := range wantLines {
+		if errs[i].Pos.Line != line {
+			t.Errorf("error %d: expected line %d, got %d", i, line, errs[i].Pos.Line)
+		}
+	}
+	if !errors.Is(err, parser.ErrInvalidStructDecl) {
+		t.Errorf("expected error list to contain %v", parser.ErrInvalidStructDecl)
+	}
+}
+
+func TestParserScannerErrorPos(t *testing.T) {
+	src := "функция ештеңе негізгі() {\n    жаз(\"сәлем)\n}\n"
+	_, err := parser.New("test.құрт", []byte(src)).Parse()
+	var errs parser.ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected parser.ErrorList, got %v", err)
+	}
+	if !errors.Is(errs[0], scanner.ErrUnterminatedString) {
+		t.Errorf("expected %v, got %v", scanner.ErrUnterminatedString, errs[0].Err)
+	}
+	// the error points to the opening quote
+	if pos := errs[0].Pos; pos.Line != 2 || pos.Col != 9 {
+		t.Errorf("expected error at 2:9, got %v", pos)
+	}
+}
+
+func TestParserWithoutSemicolons(t *testing.T) {
+	src := `құрылым нүкте {
+    х бүтін,
+}
+
+құрылым адам {
+    аты жол,
+    жасы бүтін
+}
+
+функция бүтін көбейт(
+    а бүтін,
+    б бүтін
+) {
+    қайтар а * б
+}
+
+функция бүтін қос(а бүтін, б бүтін) {
+    қайтар а +
+        б
+}
+
+функция ештеңе негізгі() {
+    айнымалы н нүкте
+    н.х = қос(1, 2); жаз(н.х)
+    егер (н.х > 2) {
+        жаз("үлкен")
+    } әйтпесе { қайтар }
+}`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decls) != 5 {
+		t.Fatalf("expected 5 declarations, got %d", len(decls))
+	}
+	if fields := decls[1].(*ast.StructDecl).Fields; len(fields) != 2 {
+		t.Errorf("expected 2 fields in адам, got %d", len(fields))
+	}
+	if args := decls[2].(*ast.FuncDecl).Args; len(args) != 2 {
+		t.Errorf("expected 2 arguments of көбейт, got %d", len(args))
+	}
+	if body := decls[4].(*ast.FuncDecl).Body; len(body) != 4 {
+		t.Errorf("expected 4 statements in негізгі, got %d", len(body))
+	}
+}
+
+func TestParserNumbers(t *testing.T) {
+	src := `айнымалы а [6]бөлшек = {1e3, 2.5E-1, 1_000.5, 0.0, 7e+0, 1e-45}
+айнымалы б [6]бүтін = {0xFF, 0b1010, 0o17, 1_000_000, 007, 9223372036854775807}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	floats := []float32{1000, 0.25, 1000.5, 0, 7, 1e-45}
+	for i, elem := range decls[0].(*ast.VarDecl).Var.Val.(*ast.ArrayExpr).Elements {
+		if got := elem.(*ast.FloatExpr).Value; got != floats[i] {
+			t.Errorf("float %d: expected %v, got %v", i, floats[i], got)
+		}
+	}
+	ints := []int{255, 10, 15, 1000000, 7, 9223372036854775807}
+	for i, elem := range decls[1].(*ast.VarDecl).Var.Val.(*ast.ArrayExpr).Elements {
+		if got := elem.(*ast.IntExpr).Value; got != ints[i] {
+			t.Errorf("int %d: expected %v, got %v", i, ints[i], got)
+		}
+	}
+
+	for _, tt := range []struct {
+		lit string
+		err error
+	}{
+		{"9223372036854775808", parser.ErrIntOverflow},
+		{"0x1_0000_0000_0000_0000", parser.ErrIntOverflow},
+		{"1e39", parser.ErrFloatRange},
+		{"1e-46", parser.ErrFloatRange},
+	} {
+		src := "айнымалы а бүтін = " + tt.lit + "\n"
+		_, err := parser.New("test.құрт", []byte(src)).Parse()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.lit, tt.err, err)
+		}
+	}
+}
+
+func TestParserPositions(t *testing.T) {
+	src := "функция ештеңе негізгі() {\n\tайнымалы а бүтін = 1 + 2;\n\tжаз(а.б[0]);\n}\n"
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := decls[0].(*ast.FuncDecl)
+	varStmt := fn.Body[0].(*ast.VarStmt)
+	call := fn.Body[1].(*ast.CallStmt).CallExpr
+	index := call.Args[0].(*ast.ArrayAccessExpr)
+
+	tests := []struct {
+		name       string
+		node       ast.Node
+		start, end [2]int // line, col
+	}{
+		{"func decl", fn, [2]int{1, 1}, [2]int{4, 2}},
+		{"var stmt", varStmt, [2]int{2, 2}, [2]int{2, 26}},
+		{"binary expr", varStmt.Val, [2]int{2, 21}, [2]int{2, 26}},
+		{"type", varStmt.Type, [2]int{2, 13}, [2]int{2, 18}},
+		{"call", call, [2]int{3, 2}, [2]int{3, 13}},
+		{"index expr", index, [2]int{3, 6}, [2]int{3, 12}},
+		{"selector", index.Array, [2]int{3, 6}, [2]int{3, 9}},
+		{"index", index.Index, [2]int{3, 10}, [2]int{3, 11}},
+	}
+	for _, tt := range tests {
+		start, end := tt.node.Pos(), tt.node.End()
+		if start.Line != tt.start[0] || start.Col != tt.start[1] {
+			t.Errorf("%s: start got %v, want %v", tt.name, start, tt.start)
+		}
+		if end.Line != tt.end[0] || end.Col != tt.end[1] {
+			t.Errorf("%s: end got %v, want %v", tt.name, end, tt.end)
+		}
+		if got := src[start.Offset:end.Offset]; start.Offset >= end.Offset {
+			t.Errorf("%s: empty source range %q", tt.name, got)
+		}
+	}
+}
+
+func TestParserExpressions(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+	а = (1 + 2) * -(3);
+	кітаптар()[0].атауы += "!";
+	(а).б[1]--;
+	қайтала(айнымалы и бүтін = 0; и < 3; и++) {
+	}
+}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decls[0].(*ast.FuncDecl).Body
+
+	mul := body[0].(*ast.AssignStmt).Val.(*ast.OpExpr)
+	if mul.Op != token.MUL {
+		t.Errorf("expected * at the top, got %v", mul.Op)
+	}
+	if _, ok := mul.Left.(*ast.ParenExpr); !ok {
+		t.Errorf("expected (1 + 2) on the left, got %T", mul.Left)
+	}
+
+	assign := body[1].(*ast.AssignStmt)
+	if assign.Op != token.ADD {
+		t.Errorf("expected += to have the operator +, got %v", assign.Op)
+	}
+	field := assign.Var.(*ast.SelectorExpr)
+	index := field.Struct.(*ast.ArrayAccessExpr)
+	if _, ok := index.Array.(*ast.CallExpr); !ok {
+		t.Errorf("expected кітаптар()[0] to index a call, got %T", index.Array)
+	}
+
+	dec := body[2].(*ast.IncDecStmt)
+	if dec.Op != token.DEC {
+		t.Errorf("expected --, got %v", dec.Op)
+	}
+	if _, ok := dec.Var.(*ast.ArrayAccessExpr).Array.(*ast.SelectorExpr).Struct.(*ast.ParenExpr); !ok {
+		t.Errorf("expected (а).б[1] to select from a parenthesised name")
+	}
+
+	if post, ok := body[3].(*ast.ForStmt).Post.(*ast.IncDecStmt); !ok || post.Op != token.INC {
+		t.Errorf("expected и++ in the post statement, got %#v", body[3].(*ast.ForStmt).Post)
+	}
+}
+
+func TestParserLoops(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+	қайтала {
+	}
+	қайтала(а < 3) {
+	}
+	қайтала(элемент : тізім) {
+	}
+	қайтала(кілт, мән : сөздік) {
+	}
+	қайтала(і : 0..ұзындық(т)) {
+	}
+}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decls[0].(*ast.FuncDecl).Body
+
+	if loop := body[0].(*ast.ForStmt); loop.Init != nil || loop.Cond != nil || loop.Post != nil {
+		t.Errorf("expected an infinite loop, got %+v", loop)
+	}
+	if loop := body[1].(*ast.ForStmt); loop.Init != nil || loop.Cond == nil || loop.Post != nil {
+		t.Errorf("expected a while loop, got %+v", loop)
+	}
+	if loop := body[2].(*ast.ForEachStmt); loop.Key != nil || loop.Value.Value != "элемент" {
+		t.Errorf("expected a for-each over элемент, got %+v", loop)
+	}
+	if loop := body[3].(*ast.ForEachStmt); loop.Key.Value != "кілт" || loop.Value.Value != "мән" {
+		t.Errorf("expected a for-each over кілт and мән, got %+v", loop)
+	}
+	if rng, ok := body[4].(*ast.ForEachStmt).X.(*ast.RangeExpr); !ok {
+		t.Errorf("expected a range, got %T", body[4].(*ast.ForEachStmt).X)
+	} else if _, ok := rng.To.(*ast.CallExpr); !ok {
+		t.Errorf("expected the range to end at a call, got %T", rng.To)
+	}
+
+	for _, src := range []string{
+		"функция ештеңе негізгі() {\n\tқайтала(а.б : т) {\n\t}\n}\n",
+		"функция ештеңе негізгі() {\n\tқайтала(а, 1 : т) {\n\t}\n}\n",
+		"функция ештеңе негізгі() {\n\tқайтала(а, б) {\n\t}\n}\n",
+	} {
+		_, err := parser.New("test.құрт", []byte(src)).Parse()
+		if !errors.Is(err, parser.ErrInvalidForEach) {
+			t.Errorf("%q: expected %v, got %v", src, parser.ErrInvalidForEach, err)
+		}
+	}
+}
+
+func TestParserSwitch(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+	таңда(а + 1) {
+	жағдай 1, 2:
+		жаз(1);
+		жаз(2);
+	әдепкі:
+	жағдай 3:
+	}
+}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := decls[0].(*ast.FuncDecl).Body[0].(*ast.SwitchStmt)
+	if _, ok := stmt.Tag.(*ast.OpExpr); !ok {
+		t.Errorf("expected а + 1 to be the tag, got %T", stmt.Tag)
+	}
+	if len(stmt.Cases) != 3 {
+		t.Fatalf("expected 3 cases, got %d", len(stmt.Cases))
+	}
+	if c := stmt.Cases[0]; len(c.Values) != 2 || len(c.Body) != 2 {
+		t.Errorf("expected 2 values and 2 statements in the first case, got %+v", c)
+	}
+	if c := stmt.Cases[1]; c.Values != nil || len(c.Body) != 0 {
+		t.Errorf("expected an empty әдепкі, got %+v", c)
+	}
+
+	for _, src := range []string{
+		"функция ештеңе негізгі() {\n\tтаңда(а) {\n\tжаз(1);\n\t}\n}\n",
+		"функция ештеңе негізгі() {\n\tтаңда(а) {\n\tжағдай:\n\t}\n}\n",
+		"функция ештеңе негізгі() {\n\tтаңда(а) {\n\tжағдай 1\n\t}\n}\n",
+		"функция ештеңе негізгі() {\n\tтаңда(а) {\n\tәдепкі\n\t}\n}\n",
+	} {
+		_, err := parser.New("test.құрт", []byte(src)).Parse()
+		if !errors.Is(err, parser.ErrInvalidSwitch) {
+			t.Errorf("%q: expected %v, got %v", src, parser.ErrInvalidSwitch, err)
+		}
+	}
+}
+
+func TestParserLabels(t *testing.T) {
+	src := `функция ештеңе негізгі() {
+	сыртқы: қайтала(і : 0..3) {
+		ішкі:
+		қайтала {
+			өткіз сыртқы;
+			тоқта ішкі;
+			тоқта;
+		}
+	}
+}
+`
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outer := decls[0].(*ast.FuncDecl).Body[0].(*ast.LabeledStmt)
+	if outer.Label.Value != "сыртқы" {
+		t.Errorf("expected the label сыртқы, got %q", outer.Label.Value)
+	}
+	inner := outer.Stmt.(*ast.ForEachStmt).Body[0].(*ast.LabeledStmt)
+	body := inner.Stmt.(*ast.ForStmt).Body
+	if cont := body[0].(*ast.ContinueStmt); cont.Label == nil || cont.Label.Value != "сыртқы" {
+		t.Errorf("expected өткіз сыртқы, got %+v", cont)
+	}
+	if brk := body[1].(*ast.BreakStmt); brk.Label == nil || brk.Label.Value != "ішкі" {
+		t.Errorf("expected тоқта ішкі, got %+v", brk)
+	}
+	if brk := body[2].(*ast.BreakStmt); brk.Label != nil {
+		t.Errorf("expected тоқта without a label, got %+v", brk.Label)
+	}
+
+	_, err = parser.New("test.құрт", []byte("функция ештеңе негізгі() {\n\tа: жаз(1);\n}\n")).Parse()
+	if !errors.Is(err, parser.ErrInvalidLabel) {
+		t.Errorf("expected %v, got %v", parser.ErrInvalidLabel, err)
+	}
+}
+
+func TestParserTypes(t *testing.T) {
+	src := "айнымалы а []бүтін\nайнымалы б [3]жол\nайнымалы в сөздік[жол][]бүтін = {\"а\": {1}, \"б\": {2, 3},}\n"
+	decls, err := parser.New("test.құрт", []byte(src)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ := decls[0].(*ast.VarDecl).Var.Type; !typ.IsList() {
+		t.Errorf("expected []бүтін to be a list, got %+v", typ)
+	}
+	if typ := decls[1].(*ast.VarDecl).Var.Type; typ.IsList() || typ.ArrayLen != 3 {
+		t.Errorf("expected [3]жол to be an array of 3, got %+v", typ)
+	}
+	mapVar := decls[2].(*ast.VarDecl).Var
+	if typ := mapVar.Type; typ.Kind != ast.TMap || typ.Key.Kind != ast.TString || !typ.Value.IsList() {
+		t.Errorf("expected сөздік[жол][]бүтін to be a map of strings to lists, got %+v", typ)
+	}
+	if lit := mapVar.Val.(*ast.MapExpr); len(lit.Keys) != 2 || len(lit.Values[1].(*ast.ArrayExpr).Elements) != 2 {
+		t.Errorf("expected a map literal of 2 keys, got %+v", lit)
+	}
+
+	for _, tt := range []struct {
+		src string
+		err error
+	}{
+		{"айнымалы а [0]бүтін\n", parser.ErrInvalidArrayLen},
+		{"айнымалы а сөздік бүтін\n", parser.ErrInvalidMapType},
+		{"айнымалы а сөздік[жол]бүтін = {\"а\": 1, \"б\"}\n", parser.ErrInvalidMap},
+		{"айнымалы а []бүтін = {1 2}\n", parser.ErrInvalidArray},
+	} {
+		_, err := parser.New("test.құрт", []byte(tt.src)).Parse()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%q: expected %v, got %v", tt.src, tt.err, err)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
+
 	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/help"
 	"github.com/nurtai325/qurtc/internal/token"
 )
 
@@ -10,81 +13,162 @@ func (p *parser) stmt() (ast.Stmt, error) {
 	if err != nil {
 		return nil, err
 	}
+	if next, _ := p.s.PeekN(2); tok == token.IDENT && next == token.COLON {
+		return p.labeledStmt()
+	}
 	switch tok {
-	case token.IDENT:
-		stmt, err := p.assignStmt()
+	case token.IDENT, token.LPAREN:
+		stmt, err := p.simpleStmt()
 		if err != nil {
 			return nil, err
 		}
-		if _, err = p.expect(token.SEMICOLON); err != nil {
+		if err = p.stmtEnd(); err != nil {
 			return nil, err
 		}
 		return stmt, nil
-	case token.VAR:
-		p.expect(token.VAR)
-		return p.varStmt()
+	case token.VAR, token.CONST:
+		p.expect(tok)
+		return p.varStmt(p.s.Pos(), tok == token.CONST)
 	case token.IF:
 		p.expect(token.IF)
-		return p.ifStmt()
+		return p.ifStmt(p.s.Pos())
 	case token.FOR:
 		p.expect(token.FOR)
-		return p.forStmt()
+		return p.forStmt(p.s.Pos())
+	case token.SWITCH:
+		p.expect(token.SWITCH)
+		return p.switchStmt(p.s.Pos())
 	case token.CONTINUE:
 		p.expect(token.CONTINUE)
-		_, err = p.expect(token.SEMICOLON)
+		start := p.s.Pos()
+		label, err := p.label()
 		if err != nil {
 			return nil, err
 		}
-		return &ast.ContinueStmt{}, nil
+		stmt := &ast.ContinueStmt{Label: label}
+		stmt.Span = p.span(start)
+		if err = p.stmtEnd(); err != nil {
+			return nil, err
+		}
+		return stmt, nil
 	case token.BREAK:
 		p.expect(token.BREAK)
-		_, err = p.expect(token.SEMICOLON)
+		start := p.s.Pos()
+		label, err := p.label()
 		if err != nil {
 			return nil, err
 		}
-		return &ast.BreakStmt{}, nil
+		stmt := &ast.BreakStmt{Label: label}
+		stmt.Span = p.span(start)
+		if err = p.stmtEnd(); err != nil {
+			return nil, err
+		}
+		return stmt, nil
 	case token.RETURN:
 		p.expect(token.RETURN)
-		val, err := p.expr(0)
-		if err != nil {
-			return nil, err
+		start := p.s.Pos()
+		stmt := &ast.ReturnStmt{}
+		if tok, _ := p.peek(); tok != token.SEMICOLON && tok != token.RBRACE {
+			stmt.Value, err = p.expr(0)
+			if err != nil {
+				return nil, err
+			}
 		}
-		_, err = p.expect(token.SEMICOLON)
-		if err != nil {
+		stmt.Span = p.span(start)
+		if err = p.stmtEnd(); err != nil {
 			return nil, err
 		}
-		return &ast.ReturnStmt{
-			Value: val,
-		}, nil
+		return stmt, nil
 	default:
+		if tok != token.LBRACE {
+			// consume the token so that the error points to it
+			p.s.Scan()
+		}
 		return nil, ErrUnknownStmt
 	}
 }
 
+// labeledStmt parses a loop with a label, сыртқы: қайтала (...) { }.
+func (p *parser) labeledStmt() (*ast.LabeledStmt, error) {
+	label, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	p.expect(token.COLON)
+	if _, err = p.expect(token.FOR); err != nil {
+		return nil, errors.Join(ErrInvalidLabel, err)
+	}
+	loop, err := p.forStmt(p.s.Pos())
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.LabeledStmt{
+		Label: label,
+		Stmt:  loop,
+	}
+	stmt.Span = p.span(label.Pos())
+	return stmt, nil
+}
+
+// label parses the label after тоқта or өткіз, nil if there is none.
+func (p *parser) label() (*ast.NameExpr, error) {
+	if tok, _ := p.peek(); tok != token.IDENT {
+		return nil, nil
+	}
+	return p.name()
+}
+
 func (p *parser) block() ([]ast.Stmt, error) {
 	if _, err := p.expect(token.LBRACE); err != nil {
 		return nil, err
 	}
+	stmts, err := p.stmtList()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(token.RBRACE); err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+// stmtList parses statements up to the '}' closing the block or the next
+// жағдай or әдепкі of a таңда, which is left unread.
+func (p *parser) stmtList() ([]ast.Stmt, error) {
 	var stmts []ast.Stmt
 	for {
 		tok, err := p.peek()
-		if err != nil {
+		if tok == token.ILLEGAL {
+			p.skip()
+			continue
+		} else if err != nil {
 			return nil, err
 		}
-		if tok == token.RBRACE {
-			p.expect(token.RBRACE)
+		if tok == token.RBRACE || tok == token.CASE || tok == token.DEFAULT {
 			break
 		}
+		if tok == token.SEMICOLON {
+			// empty statement, usually inserted after a closing brace
+			p.expect(token.SEMICOLON)
+			continue
+		}
 		stmt, err := p.stmt()
 		if err != nil {
-			return nil, err
+			if errors.Is(err, errSync) {
+				return nil, err
+			}
+			p.errorAt(err, help.SyntaxPage)
+			if !p.syncStmt() {
+				return nil, errSync
+			}
+			continue
 		}
 		stmts = append(stmts, stmt)
 	}
 	return stmts, nil
 }
 
-func (p *parser) ifStmt() (*ast.IfStmt, error) {
+func (p *parser) ifStmt(start token.Pos) (*ast.IfStmt, error) {
 	var stmt *ast.IfStmt = &ast.IfStmt{}
 
 	_, err := p.expect(token.LPAREN)
@@ -115,8 +199,8 @@ func (p *parser) ifStmt() (*ast.IfStmt, error) {
 			return nil, err
 		}
 		if tok == token.IF {
-			p.expect()
-			stmt.Else, err = p.ifStmt()
+			p.expect(token.IF)
+			stmt.Else, err = p.ifStmt(p.s.Pos())
 			if err != nil {
 				return nil, err
 			}
@@ -128,19 +212,123 @@ func (p *parser) ifStmt() (*ast.IfStmt, error) {
 			stmt.Else = ast.Stmts(stmtElse)
 		}
 	}
+	stmt.Span = p.span(start)
 	return stmt, nil
 }
 
-func (p *parser) forStmt() (*ast.ForStmt, error) {
+// switchStmt parses таңда (мән) { жағдай 1, 2: ... әдепкі: ... } after таңда.
+func (p *parser) switchStmt(start token.Pos) (*ast.SwitchStmt, error) {
 	_, err := p.expect(token.LPAREN)
 	if err != nil {
 		return nil, err
 	}
-	_, err = p.expect(token.VAR)
+	tag, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.expect(token.RPAREN)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.expect(token.LBRACE)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.SwitchStmt{Tag: tag}
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok == token.SEMICOLON {
+			p.expect(token.SEMICOLON)
+			continue
+		}
+		if tok == token.RBRACE {
+			p.expect(token.RBRACE)
+			break
+		}
+		if _, err := p.expect(token.CASE, token.DEFAULT); err != nil {
+			return nil, errors.Join(ErrInvalidSwitch, err)
+		}
+		clause := &ast.CaseClause{}
+		caseStart := p.s.Pos()
+		if tok == token.CASE {
+			clause.Values, err = p.exprList(token.COLON)
+			if err == nil && len(clause.Values) == 0 {
+				return nil, ErrInvalidSwitch
+			}
+		} else {
+			_, err = p.expect(token.COLON)
+		}
+		if err != nil {
+			return nil, errors.Join(ErrInvalidSwitch, err)
+		}
+		clause.Body, err = p.stmtList()
+		if err != nil {
+			return nil, err
+		}
+		clause.Span = p.span(caseStart)
+		stmt.Cases = append(stmt.Cases, clause)
+	}
+	stmt.Span = p.span(start)
+	return stmt, nil
+}
+
+// forStmt parses the loops after қайтала: the infinite қайтала { }, the
+// while loop қайтала (шарт), the for-each қайтала (элемент : тізім) and
+// қайтала (айнымалы і бүтін = 0; і < 10; і++).
+func (p *parser) forStmt(start token.Pos) (ast.Stmt, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok == token.LBRACE {
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt := &ast.ForStmt{Body: body}
+		stmt.Span = p.span(start)
+		return stmt, nil
+	}
+	_, err = p.expect(token.LPAREN)
 	if err != nil {
 		return nil, err
 	}
-	init, err := p.varStmt()
+	tok, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	if tok != token.VAR {
+		x, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		tok, err = p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok == token.COMMA || tok == token.COLON {
+			return p.forEachStmt(start, x)
+		}
+		_, err = p.expect(token.RPAREN)
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt := &ast.ForStmt{
+			Cond: x,
+			Body: body,
+		}
+		stmt.Span = p.span(start)
+		return stmt, nil
+	}
+	p.expect(token.VAR)
+	init, err := p.varStmt(p.s.Pos(), false)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +340,7 @@ func (p *parser) forStmt() (*ast.ForStmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	post, err := p.assignStmt()
+	post, err := p.simpleStmt()
 	if err != nil {
 		return nil, err
 	}
@@ -164,15 +352,62 @@ func (p *parser) forStmt() (*ast.ForStmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ast.ForStmt{
+	stmt := &ast.ForStmt{
 		Init: init,
 		Cond: cond,
 		Post: post,
 		Body: body,
-	}, nil
+	}
+	stmt.Span = p.span(start)
+	return stmt, nil
+}
+
+// forEachStmt parses the rest of қайтала (кілт, мән : сөздік) after the
+// first name.
+func (p *parser) forEachStmt(start token.Pos, first ast.Expr) (*ast.ForEachStmt, error) {
+	value, ok := first.(*ast.NameExpr)
+	if !ok {
+		return nil, ErrInvalidForEach
+	}
+	stmt := &ast.ForEachStmt{Value: value}
+	if tok, _ := p.peek(); tok == token.COMMA {
+		p.expect(token.COMMA)
+		name, err := p.name()
+		if err != nil {
+			return nil, errors.Join(ErrInvalidForEach, err)
+		}
+		stmt.Key, stmt.Value = value, name
+	}
+	if _, err := p.expect(token.COLON); err != nil {
+		return nil, errors.Join(ErrInvalidForEach, err)
+	}
+	x, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok, _ := p.peek(); tok == token.DOTDOT {
+		p.expect(token.DOTDOT)
+		to, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		rng := &ast.RangeExpr{From: x, To: to}
+		rng.Span = p.span(x.Pos())
+		x = rng
+	}
+	stmt.X = x
+	if _, err = p.expect(token.RPAREN); err != nil {
+		return nil, err
+	}
+	if stmt.Body, err = p.block(); err != nil {
+		return nil, err
+	}
+	stmt.Span = p.span(start)
+	return stmt, nil
 }
 
-func (p *parser) varStmt() (*ast.VarStmt, error) {
+// varStmt parses a variable or constant declaration after the keyword at start.
+func (p *parser) varStmt(start token.Pos, isConst bool) (*ast.VarStmt, error) {
 	varName, err := p.name()
 	if err != nil {
 		return nil, err
@@ -185,12 +420,17 @@ func (p *parser) varStmt() (*ast.VarStmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	if tok == token.SEMICOLON {
-		p.expect(token.SEMICOLON)
-		return &ast.VarStmt{
+	if tok == token.SEMICOLON || tok == token.RBRACE {
+		if isConst {
+			p.stmtEnd()
+			return nil, ErrConstWithoutValue
+		}
+		stmt := &ast.VarStmt{
 			Name: varName,
 			Type: varType,
-		}, nil
+		}
+		stmt.Span = p.span(start)
+		return stmt, p.stmtEnd()
 	}
 	if _, err = p.expect(token.ASSIGN); err != nil {
 		return nil, err
@@ -199,33 +439,79 @@ func (p *parser) varStmt() (*ast.VarStmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	if _, err = p.expect(token.SEMICOLON); err != nil {
+	stmt := &ast.VarStmt{
+		Name:  varName,
+		Type:  varType,
+		Val:   val,
+		Const: isConst,
+	}
+	stmt.Span = p.span(start)
+	if err = p.stmtEnd(); err != nil {
 		return nil, err
 	}
-	return &ast.VarStmt{
-		Name: varName,
-		Type: varType,
-		Val:  val,
-	}, nil
+	return stmt, nil
 }
 
-func (p *parser) assignStmt() (ast.Stmt, error) {
-	assignee, err := p.nameExpr()
+// stmtEnd consumes the ';' ending a statement. It can be left out before
+// the '}' closing the block, like in "егер (а) { тоқта }".
+func (p *parser) stmtEnd() error {
+	if tok, _ := p.peek(); tok == token.RBRACE {
+		return nil
+	}
+	_, err := p.expect(token.SEMICOLON)
+	return err
+}
+
+// assignOps are the binary operators of the compound assignments.
+var assignOps = map[token.Token]token.Token{
+	token.ADD_ASSIGN: token.ADD,
+	token.SUB_ASSIGN: token.SUB,
+	token.MUL_ASSIGN: token.MUL,
+	token.DIV_ASSIGN: token.DIV,
+	token.MOD_ASSIGN: token.MOD,
+}
+
+// simpleStmt parses an assignment, an increment, a decrement or a call,
+// the statements that can be the post statement of a loop.
+func (p *parser) simpleStmt() (ast.Stmt, error) {
+	x, err := p.expr(0)
 	if err != nil {
 		return nil, err
 	}
-	if call, ok := assignee.(*ast.CallExpr); ok {
-		return &ast.CallStmt{
-			CallExpr: call,
-		}, nil
-	}
-	p.expect(token.ASSIGN)
-	val, err := p.expr(0)
+	tok, err := p.peek()
 	if err != nil {
 		return nil, err
 	}
-	return &ast.AssignStmt{
-		Var: assignee,
-		Val: val,
-	}, nil
+	if op, ok := assignOps[tok]; ok || tok == token.ASSIGN {
+		p.expect(tok)
+		val, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		stmt := &ast.AssignStmt{
+			Var: x,
+			Op:  op,
+			Val: val,
+		}
+		stmt.Span = p.span(x.Pos())
+		return stmt, nil
+	}
+	if tok == token.INC || tok == token.DEC {
+		p.expect(tok)
+		stmt := &ast.IncDecStmt{
+			Var: x,
+			Op:  tok,
+		}
+		stmt.Span = p.span(x.Pos())
+		return stmt, nil
+	}
+	if call, ok := x.(*ast.CallExpr); ok {
+		stmt := &ast.CallStmt{
+			CallExpr: call,
+		}
+		stmt.Span = call.Span
+		return stmt, nil
+	}
+	_, err = p.expect(token.ASSIGN)
+	return nil, err
 }
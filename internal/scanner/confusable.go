@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+// scripts are the alphabets Kazakh is written in.
+var scripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"кирилл", unicode.Cyrillic},
+	{"латын", unicode.Latin},
+	{"араб", unicode.Arabic},
+}
+
+// dialectScripts are the indexes in scripts of the alphabets of dialects.
+var dialectScripts = [...]int{
+	token.Cyrillic: 0,
+	token.Latin:    1,
+	token.Arabic:   2,
+}
+
+// confusables are the Latin letters that look like Cyrillic ones.
+var confusables = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'h': 'һ', 'i': 'і', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'I': 'І', 'K': 'К', 'M': 'М', 'O': 'О',
+	'P': 'Р', 'T': 'Т', 'X': 'Х', 'Y': 'У',
+}
+
+// latinConfusables are confusables the other way round.
+var latinConfusables = make(map[rune]rune, len(confusables))
+
+func init() {
+	for latin, cyrillic := range confusables {
+		latinConfusables[cyrillic] = latin
+	}
+}
+
+func scriptOf(ch rune) int {
+	for i, script := range scripts {
+		if unicode.Is(script.table, ch) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mixedScripts checks that the letters of the identifier lit are from one
+// alphabet. Usually a letter from another keyboard layout slipped in, so
+// the offset in lit of the first letter that is not from the alphabet of
+// most letters is returned with an error suggesting the intended letter.
+func (s *scanner) mixedScripts(lit string) (int, error) {
+	counts := make([]int, len(scripts))
+	for _, ch := range lit {
+		if i := scriptOf(ch); i >= 0 {
+			counts[i]++
+		}
+	}
+	// on a tie the alphabet of the keywords wins
+	main, used := dialectScripts[s.dialect], 0
+	for i, n := range counts {
+		if n > counts[main] {
+			main = i
+		}
+		if n != 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		return 0, nil
+	}
+	for offset, ch := range lit {
+		script := scriptOf(ch)
+		if script < 0 || script == main {
+			continue
+		}
+		err := fmt.Errorf("%w: %q атауындағы %q %s әрпі", ErrMixedScripts, lit, ch, scripts[script].name)
+		suggest, ok := rune(0), false
+		switch scripts[main].table {
+		case unicode.Cyrillic:
+			suggest, ok = confusables[ch]
+		case unicode.Latin:
+			suggest, ok = latinConfusables[ch]
+		}
+		if ok {
+			err = fmt.Errorf("%w, оның орнына %s %q әрпін жазу керек шығар", err, scripts[main].name, suggest)
+		}
+		return offset, err
+	}
+	return 0, nil
+}
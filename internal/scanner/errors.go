@@ -5,6 +5,18 @@ import "errors"
 var (
 	ErrInvalidCharacter  = errors.New("рұқсат етілмеген таңба. Жазылған таңбаны тану мүмкін болмады")
 	ErrInvalidIdentifier = errors.New("рұқсат етілмеген айнымалы немесе функция атауы. атау әріптен ғана басталып, ары қарай әріптер мен цифрлардан тұру керек. мысалы: 'атау', 'атау1', 'Атау12', 'АТАУ1', 'h2o'")
+	ErrMixedScripts      = errors.New("атауда әртүрлі әліпбидің әріптері аралас, пернетақта тілі ауысып кеткен болуы мүмкін")
 	ErrSingleAmpersand   = errors.New("және операторын қолдану үшін & емес && қолданыңыз")
 	ErrSingleVerticalBar = errors.New("немесе операторын қолдану үшін | емес || қолданыңыз")
+
+	ErrInvalidDigit     = errors.New("санда оның санау жүйесіне жатпайтын цифр бар")
+	ErrMissingDigits    = errors.New("санның бөлігінде цифр жоқ, мысалы 0x кейін 16-лық цифр жазылуы керек")
+	ErrInvalidSeparator = errors.New("_ тек екі цифрдың арасында жазылады, мысалы 1_000_000")
+
+	ErrRead = errors.New("файлды оқу мүмкін болмады")
+
+	ErrUnterminatedComment  = errors.New("/* арқылы басталған түсініктеме */ арқылы жабылмаған")
+	ErrUnterminatedString   = errors.New("жол аяқталмаған: тырнақшамен басталған жол тырнақшамен, ` белгісімен басталған жол ` белгісімен жабылуы керек")
+	ErrInvalidEscape        = errors.New("жолдағы \\ таңбасынан кейін тек n, t, \", \\, {, } немесе u{...} жазылады")
+	ErrInvalidUnicodeEscape = errors.New("\\u{...} ішінде таңбаның 16-лық санау жүйесіндегі коды жазылуы керек, мысалы \\u{4D9}")
 )
@@ -1,64 +1,140 @@
 package scanner
 
 import (
+	"cmp"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/nurtai325/qurtc/internal/token"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Scanner interface {
 	Scan() bool
+	// Peek returns the token after the current one without scanning it.
 	Peek() (token.Token, error)
+	// PeekN returns the n-th token after the current one, PeekN(1) is Peek.
+	PeekN(n int) (token.Token, error)
 	Lit() string
 	Tok() token.Token
-	Pos() Pos
+	Pos() token.Pos
+	End() token.Pos
+	Snippet(start, end token.Pos, context int) string
 	Err() error
+	// Dialect returns the dialect of the keywords scanned so far.
+	Dialect() token.Dialect
 }
 
+// Mode controls optional scanner behaviour.
+type Mode uint
+
+const (
+	// ScanComments makes the scanner return comments as token.COMMENT
+	// instead of skipping them.
+	ScanComments Mode = 1 << iota
+)
+
+// chunkSize is how many bytes are read from the reader at once.
+const chunkSize = 4096
+
 type scanner struct {
 	filename string
-	src      []byte
-	err      error
-	cursor   int
-	line     int
-	col      int
-	tok      token.Token
-	lit      string
-	tokw     int
-}
-
-func New(filename string, src []byte) Scanner {
-	s := scanner{
+	// src is the source read so far, literals are sliced from it and it
+	// is kept whole for error snippets
+	src     []byte
+	r       io.Reader // nil after the end of the source is read
+	readErr error
+	mode    Mode
+	cursor  int
+	lines   []int  // byte offsets of line starts
+	item           // the current token
+	queue   []item // tokens scanned by PeekN and not returned by Scan yet
+	// insertSemi is set after a token that can end a statement, a newline
+	// or the end of the file after it is returned as token.SEMICOLON
+	insertSemi bool
+	interps    []interp // interpolated strings the cursor is in, innermost last
+	// dialect is set by the first keyword, the words of other dialects
+	// are identifiers after it
+	dialect      token.Dialect
+	dialectKnown bool
+}
+
+// item is a scanned token.
+type item struct {
+	tok        token.Token
+	lit        string
+	start, end int
+	err        error
+}
+
+// interp is an interpolated string whose {expression} is being scanned.
+type interp struct {
+	quote int // offset of the opening quote
+	depth int // braces opened inside the expression and not closed yet
+}
+
+// New returns a scanner of src.
+func New(filename string, src []byte, mode Mode) Scanner {
+	return &scanner{
 		filename: filename,
-		// TODO: use io.Reader and buffer instead of []byte
-		src:  src,
-		line: 1,
+		src:      src,
+		mode:     mode,
+		lines:    []int{0},
 	}
-	return &s
 }
 
-type Pos interface {
-	File() string
-	Line() int
-	Col() int
+// NewReader returns a scanner reading the source from r as it goes.
+func NewReader(filename string, r io.Reader, mode Mode) Scanner {
+	return &scanner{
+		filename: filename,
+		r:        r,
+		mode:     mode,
+		lines:    []int{0},
+	}
 }
 
-func (s *scanner) File() string {
-	return s.filename
+// fill reads from the reader until n bytes after the cursor are buffered
+// or the source ends. It reports whether there are n bytes.
+func (s *scanner) fill(n int) bool {
+	for len(s.src)-s.cursor < n && s.r != nil {
+		s.src = slices.Grow(s.src, chunkSize)
+		read, err := s.r.Read(s.src[len(s.src):cap(s.src)])
+		s.src = s.src[:len(s.src)+read]
+		if err != nil {
+			if err != io.EOF {
+				s.readErr = err
+			}
+			s.r = nil
+		}
+	}
+	return len(s.src)-s.cursor >= n
 }
 
-func (s *scanner) Line() int {
-	return s.line
+// Pos returns the position of the first character of the current token.
+func (s *scanner) Pos() token.Pos {
+	return s.pos(s.start)
 }
 
-func (s *scanner) Col() int {
-	return s.col
+// End returns the position right after the last character of the current token.
+func (s *scanner) End() token.Pos {
+	return s.pos(s.end)
 }
 
-func (s *scanner) Pos() Pos {
-	return s
+func (s *scanner) pos(offset int) token.Pos {
+	line := sort.Search(len(s.lines), func(i int) bool {
+		return s.lines[i] > offset
+	}) - 1
+	return token.Pos{
+		File:   s.filename,
+		Line:   line + 1,
+		Col:    utf8.RuneCount(s.src[s.lines[line]:offset]) + 1,
+		Offset: offset,
+	}
 }
 
 func (s *scanner) Lit() string {
@@ -70,106 +146,209 @@ func (s *scanner) Tok() token.Token {
 }
 
 func (s *scanner) Scan() bool {
-	s.tokw = 0
-	ch, chw := s.nextCh()
-	for unicode.IsSpace(ch) {
-		ch, chw = s.nextCh()
+	if len(s.queue) != 0 {
+		s.item = s.queue[0]
+		s.queue = s.queue[1:]
+	} else {
+		s.scanItem()
+	}
+	return s.tok != token.EOF && s.tok != token.ILLEGAL
+}
+
+func (s *scanner) Peek() (token.Token, error) {
+	return s.PeekN(1)
+}
+
+func (s *scanner) PeekN(n int) (token.Token, error) {
+	cur := s.item
+	for len(s.queue) < n {
+		s.scanItem()
+		s.queue = append(s.queue, s.item)
+	}
+	s.item = cur
+	next := s.queue[n-1]
+	return next.tok, next.err
+}
+
+// scanItem scans the next token into s.item, skipping comments unless
+// they are asked for.
+func (s *scanner) scanItem() {
+	for {
+		// a token that ends before the cursor sets end itself
+		s.item = item{end: -1}
+		s.scan()
+		if s.end < 0 {
+			s.end = s.cursor
+		}
+		if s.tok != token.COMMENT || s.mode&ScanComments != 0 {
+			return
+		}
+	}
+}
+
+// Semicolons are inserted the way Go does it: a line ending after an
+// identifier, a literal, one of ) ] } ++ -- or қайтар, тоқта, өткіз ends the
+// statement, so beginners do not have to write the ';' themselves.
+func endsStmt(tok token.Token) bool {
+	switch tok {
+	case token.IDENT, token.INT, token.FLOAT, token.STRING, token.INTERP_END, token.TRUE, token.FALSE,
+		token.RPAREN, token.RBRACK, token.RBRACE, token.INC, token.DEC,
+		token.RETURN, token.BREAK, token.CONTINUE:
+		return true
+	}
+	return false
+}
+
+func (s *scanner) scan() {
+	ch, _ := s.peekCh()
+	for unicode.IsSpace(ch) && (ch != '\n' || !s.insertSemi) {
+		s.nextCh()
+		ch, _ = s.peekCh()
+	}
+	s.start = s.cursor
+
+	if s.insertSemi && (ch == '\n' || ch == -1 || s.commentEndsLine(ch)) {
+		// the newline, the comment or the end of the file is scanned next
+		s.insertSemi = false
+		s.lit, s.tok = "\n", token.SEMICOLON
+		return
+	}
+	s.scanToken(ch)
+	if s.tok != token.COMMENT {
+		// expressions in a string may span lines without ending a statement
+		s.insertSemi = endsStmt(s.tok) && len(s.interps) == 0
+	}
+}
+
+// commentEndsLine reports whether ch starts a comment after which the
+// line ends: a line comment or a block comment spanning several lines.
+func (s *scanner) commentEndsLine(ch rune) bool {
+	if ch != '/' {
+		return false
 	}
+	switch s.peekByte(1) {
+	case '/':
+		return true
+	case '*':
+		for i := 2; s.fill(i + 1); i++ {
+			switch {
+			case s.peekByte(i) == '\n':
+				return true
+			case s.peekByte(i) == '*' && s.peekByte(i+1) == '/':
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
 
+// scanToken scans the token starting with ch, the character at the cursor.
+func (s *scanner) scanToken(ch rune) {
 	if unicode.IsLetter(ch) {
-		s.back(chw)
 		s.ident()
-		return true
+		return
 	}
-	if unicode.IsDigit(ch) {
-		s.back(chw)
+	if ch < utf8.RuneSelf && isDigit(byte(ch)) {
 		s.numberLit()
-		return true
+		return
 	}
 
+	s.nextCh()
 	switch ch {
 	case -1:
-		s.lit = token.EOF.String()
-		s.tok = token.EOF
+		switch {
+		case len(s.interps) != 0:
+			// the string of the expression is not closed
+			s.start = s.interps[0].quote
+			s.interps = nil
+			s.err = ErrUnterminatedString
+			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+		case s.readErr != nil:
+			s.err = fmt.Errorf("%w: %w", ErrRead, s.readErr)
+			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+		default:
+			s.lit, s.tok = token.EOF.String(), token.EOF
+		}
 	case '"':
-		s.back(chw)
-		s.stringLit()
+		s.stringLit(s.start, true)
+	case '`':
+		s.rawStringLit()
 	case '+':
-		s.lit, s.tok = token.ADD.String(), token.ADD
+		if s.accept('+') {
+			s.lit, s.tok = token.INC.String(), token.INC
+		} else {
+			s.either('=', token.ADD_ASSIGN, token.ADD)
+		}
 	case '-':
-		s.lit, s.tok = token.SUB.String(), token.SUB
+		if s.accept('-') {
+			s.lit, s.tok = token.DEC.String(), token.DEC
+		} else {
+			s.either('=', token.SUB_ASSIGN, token.SUB)
+		}
 	case '*':
-		s.lit, s.tok = token.MUL.String(), token.MUL
+		s.either('=', token.MUL_ASSIGN, token.MUL)
 	case '/':
-		// TODO: add comment consuming and returning comment token here
-		s.lit, s.tok = token.DIV.String(), token.DIV
+		switch {
+		case s.accept('/'):
+			s.lineComment()
+		case s.accept('*'):
+			s.blockComment()
+		default:
+			s.either('=', token.DIV_ASSIGN, token.DIV)
+		}
 	case '%':
-		s.lit, s.tok = token.MOD.String(), token.MOD
+		s.either('=', token.MOD_ASSIGN, token.MOD)
 	case '&':
-		ch, chw := s.nextCh()
-		if ch == '&' {
+		if s.accept('&') {
 			s.lit, s.tok = token.LAND.String(), token.LAND
 		} else {
-			s.back(chw)
 			s.err = ErrSingleAmpersand
 			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
 		}
 	case '|':
-		ch, chw := s.nextCh()
-		if ch == '|' {
+		if s.accept('|') {
 			s.lit, s.tok = token.LOR.String(), token.LOR
 		} else {
-			s.back(chw)
 			s.err = ErrSingleVerticalBar
 			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
 		}
 	case '=':
-		ch, chw := s.nextCh()
-		if ch == '=' {
-			s.lit, s.tok = token.EQL.String(), token.EQL
-		} else {
-			s.back(chw)
-			s.lit, s.tok = token.ASSIGN.String(), token.ASSIGN
-		}
+		s.either('=', token.EQL, token.ASSIGN)
 	case '<':
-		ch, chw := s.nextCh()
-		if ch == '=' {
-			s.lit, s.tok = token.LEQ.String(), token.LEQ
-		} else {
-			s.back(chw)
-			s.lit, s.tok = token.LSS.String(), token.LSS
-		}
+		s.either('=', token.LEQ, token.LSS)
 	case '>':
-		ch, chw := s.nextCh()
-		if ch == '=' {
-			s.lit, s.tok = token.GEQ.String(), token.GEQ
-		} else {
-			s.back(chw)
-			s.lit, s.tok = token.GTR.String(), token.GTR
-		}
+		s.either('=', token.GEQ, token.GTR)
 	case '!':
-		ch, chw := s.nextCh()
-		if ch == '=' {
-			s.lit, s.tok = token.NEQ.String(), token.NEQ
-		} else {
-			s.back(chw)
-			s.lit, s.tok = token.NOT.String(), token.NOT
-		}
+		s.either('=', token.NEQ, token.NOT)
 	case '(':
 		s.lit, s.tok = token.LPAREN.String(), token.LPAREN
 	case '[':
 		s.lit, s.tok = token.LBRACK.String(), token.LBRACK
 	case '{':
+		if n := len(s.interps); n != 0 {
+			s.interps[n-1].depth++
+		}
 		s.lit, s.tok = token.LBRACE.String(), token.LBRACE
 	case ',':
 		s.lit, s.tok = token.COMMA.String(), token.COMMA
 	case '.':
-		s.lit, s.tok = token.PERIOD.String(), token.PERIOD
+		s.either('.', token.DOTDOT, token.PERIOD)
+	case ':':
+		s.lit, s.tok = token.COLON.String(), token.COLON
 	case ')':
 		s.lit, s.tok = token.RPAREN.String(), token.RPAREN
 	case ']':
 		s.lit, s.tok = token.RBRACK.String(), token.RBRACK
 	case '}':
+		if n := len(s.interps); n != 0 {
+			if s.interps[n-1].depth == 0 {
+				// the expression ends, the string goes on
+				s.stringLit(s.interps[n-1].quote, false)
+				break
+			}
+			s.interps[n-1].depth--
+		}
 		s.lit, s.tok = token.RBRACE.String(), token.RBRACE
 	case ';':
 		s.lit, s.tok = "semicolon", token.SEMICOLON
@@ -177,79 +356,75 @@ func (s *scanner) Scan() bool {
 		s.err = fmt.Errorf("%w: %c", ErrInvalidCharacter, ch)
 		s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
 	}
+}
 
-	if s.tok == token.EOF || s.tok == token.ILLEGAL {
+// accept consumes the next byte if it is b.
+func (s *scanner) accept(b byte) bool {
+	if s.peekByte(0) != b {
 		return false
+	}
+	s.cursor++
+	return true
+}
+
+// either scans tok if the next byte is b, like <= after <, otherwise
+// the one character token short.
+func (s *scanner) either(b byte, tok, short token.Token) {
+	if s.accept(b) {
+		s.lit, s.tok = tok.String(), tok
 	} else {
-		return true
+		s.lit, s.tok = short.String(), short
 	}
 }
 
-func (s *scanner) nextCh() (rune, int) {
+// peekCh returns the character at the cursor and its size, -1 at the end.
+func (s *scanner) peekCh() (rune, int) {
+	s.fill(utf8.UTFMax)
 	if s.cursor >= len(s.src) {
 		return -1, 0
 	}
-	r, size := utf8.DecodeRune(s.src[s.cursor:])
-	if r != utf8.RuneError {
-		if r == '\n' {
-			s.line += 1
-			s.col = 0
-		} else {
-			s.col += 1
-		}
-		s.cursor += size
-		s.tokw += size
-		return r, size
-	}
-	if size == 0 {
-		return -1, 0
-	}
-	return utf8.RuneError, 0
-}
-
-func (s *scanner) Peek() (token.Token, error) {
-	tok, lit := s.tok, s.lit
-
-	s.Scan()
-
-	nextTok := s.tok
-
-	s.back(s.tokw)
-	s.tok = tok
-	s.lit = lit
-
-	return nextTok, s.Err()
+	// invalid encoding is returned as utf8.RuneError and skipped
+	// like any other character so that scanning always moves forward
+	return utf8.DecodeRune(s.src[s.cursor:])
 }
 
-func (s *scanner) back(n int) {
-	s.cursor -= n
-	s.tokw -= n
-	s.col -= n
+// nextCh consumes the character at the cursor.
+func (s *scanner) nextCh() (rune, int) {
+	r, size := s.peekCh()
+	s.cursor += size
+	if r == '\n' {
+		s.lines = append(s.lines, s.cursor)
+	}
+	return r, size
 }
 
+// ident scans an identifier or a keyword. Identifiers are normalized to
+// NFC, so that letters typed as a base letter and a combining mark are
+// the same as the single letter, and may not mix alphabets.
 func (s *scanner) ident() {
-	lit := ""
+	start := s.cursor
 	for {
-		ch, chw := s.nextCh()
-		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
-			lit += string(ch)
-			continue
+		ch, chw := s.peekCh()
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && !unicode.In(ch, unicode.Mn, unicode.Mc) {
+			break
 		}
-		s.back(chw)
-		break
+		s.cursor += chw
 	}
-
-	if lit == token.TRUE.String() {
-		s.lit = token.TRUE.String()
-		s.tok = token.TRUE
-		return
-	} else if lit == token.FALSE.String() {
-		s.lit = token.FALSE.String()
-		s.tok = token.FALSE
+	lit := string(s.src[start:s.cursor])
+	if offset, err := s.mixedScripts(lit); err != nil {
+		// point to the letter from the other alphabet
+		_, size := utf8.DecodeRuneInString(lit[offset:])
+		s.start, s.end = start+offset, start+offset+size
+		s.err = err
+		s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
 		return
 	}
+	lit = norm.NFC.String(lit)
 
-	tok, ok := token.Lookup(lit)
+	if !s.dialectKnown {
+		s.dialect, s.dialectKnown = token.DetectDialect(lit)
+	}
+	tok, ok := token.LookupIn(lit, s.dialect)
 	if ok {
 		s.lit = lit
 		s.tok = tok
@@ -261,46 +436,281 @@ func (s *scanner) ident() {
 	}
 }
 
+// numberLit scans a number. Integers may have a 0x, 0b or 0o base prefix,
+// decimal numbers a fraction and an exponent, and digits may be separated
+// by '_'. The literal is returned as written, the parser converts it.
 func (s *scanner) numberLit() {
-	lit := ""
-	dotSeen := false
+	start := s.cursor
+	base, tok := 10, token.INT
+	if s.peekByte(0) == '0' {
+		switch lower(s.peekByte(1)) {
+		case 'x':
+			base = 16
+		case 'b':
+			base = 2
+		case 'o':
+			base = 8
+		}
+		if base != 10 {
+			s.cursor += 2
+		}
+	}
+	err := s.digits(base, base != 10)
+	if base == 10 {
+		if s.peekByte(0) == '.' && isDigit(s.peekByte(1)) {
+			s.cursor++
+			tok = token.FLOAT
+			if digitsErr := s.digits(10, false); err == nil {
+				err = digitsErr
+			}
+		}
+		sign := s.peekByte(1) == '+' || s.peekByte(1) == '-'
+		if lower(s.peekByte(0)) == 'e' && (isDigit(s.peekByte(1)) || sign && isDigit(s.peekByte(2))) {
+			s.cursor++
+			if sign {
+				s.cursor++
+			}
+			tok = token.FLOAT
+			if digitsErr := s.digits(10, false); err == nil {
+				err = digitsErr
+			}
+		}
+	}
+	lit := string(s.src[start:s.cursor])
+	if err != nil {
+		s.err = fmt.Errorf("%w: %s", err, lit)
+		s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+		return
+	}
+	s.lit, s.tok = lit, tok
+}
+
+// digits scans the digits of a number in base. Decimal digits that are too
+// big for the base are scanned too, to report them. afterPrefix allows a
+// separator before the first digit, like in 0x_FF.
+func (s *scanner) digits(base int, afterPrefix bool) error {
+	var err error
+	n := 0
+	prevSep := false
 	for {
-		ch, chw := s.nextCh()
-		if unicode.IsDigit(ch) || (!dotSeen && ch == '.') {
-			if ch == '.' {
-				dotSeen = true
+		ch := s.peekByte(0)
+		if ch == '_' {
+			if n == 0 && !afterPrefix || prevSep {
+				err = cmp.Or(err, ErrInvalidSeparator)
 			}
-			lit += string(ch)
+			prevSep = true
+			s.cursor++
 			continue
 		}
-		s.back(chw)
-		break
+		d := hexDigit(rune(ch))
+		if d < 0 || d >= 10 && base != 16 {
+			break
+		}
+		if int(d) >= base {
+			err = cmp.Or(err, fmt.Errorf("%w '%c'", ErrInvalidDigit, ch))
+		}
+		prevSep = false
+		n++
+		s.cursor++
 	}
-	if dotSeen {
-		s.lit = lit
-		s.tok = token.FLOAT
-	} else {
-		s.lit = lit
-		s.tok = token.INT
+	if n == 0 {
+		return cmp.Or(err, ErrMissingDigits)
+	}
+	if prevSep {
+		return cmp.Or(err, ErrInvalidSeparator)
+	}
+	return err
+}
+
+// peekByte returns the byte i bytes after the cursor, or 0 at the end.
+func (s *scanner) peekByte(i int) byte {
+	if !s.fill(i + 1) {
+		return 0
+	}
+	return s.src[s.cursor+i]
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func lower(ch byte) byte {
+	return ch | ('x' - 'X')
+}
+
+// stringLit scans a part of a string literal after its opening quote, or
+// after the '}' of an {expression} in it if first is false, and decodes its
+// escape sequences. A '{' starts an expression and ends the part, its text
+// is returned as token.INTERP_BEG or token.INTERP_MID; the text before the
+// closing quote is token.STRING or token.INTERP_END. An invalid escape is
+// reported after the whole part is read, so that scanning goes on after it.
+func (s *scanner) stringLit(quote int, first bool) {
+	// the text is sliced from the source, the builder is only used for
+	// parts with escapes
+	var lit strings.Builder
+	escaped := false
+	var err error
+	text := s.cursor // start of the text after the last escape
+	for {
+		ch, chw := s.peekCh()
+		if ch == -1 || ch == '\n' {
+			// the newline is left to the next token, only raw strings
+			// span lines
+			s.start = quote
+			s.interps = nil
+			s.err = ErrUnterminatedString
+			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+			return
+		}
+		s.nextCh()
+		switch ch {
+		case '"', '{':
+			if escaped {
+				lit.Write(s.src[text : s.cursor-chw])
+				s.lit = lit.String()
+			} else {
+				s.lit = string(s.src[text : s.cursor-chw])
+			}
+			switch {
+			case ch == '"' && first:
+				s.tok = token.STRING
+			case ch == '"':
+				s.interps = s.interps[:len(s.interps)-1]
+				s.tok = token.INTERP_END
+			case first:
+				s.interps = append(s.interps, interp{quote: quote})
+				s.tok = token.INTERP_BEG
+			default:
+				s.tok = token.INTERP_MID
+			}
+			if err != nil {
+				s.err = err
+				s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+			}
+			return
+		case '\\':
+			escaped = true
+			lit.Write(s.src[text : s.cursor-chw])
+			r, escErr := s.escape()
+			text = s.cursor
+			if escErr != nil {
+				err = cmp.Or(err, escErr)
+				continue
+			}
+			lit.WriteRune(r)
+		}
+	}
+}
+
+// escape decodes an escape sequence after the backslash.
+func (s *scanner) escape() (rune, error) {
+	ch, _ := s.peekCh()
+	switch ch {
+	case 'n', 't', '"', '\\', '{', '}', 'u':
+	case -1:
+		return 0, ErrInvalidEscape
+	default:
+		// the character may be the closing quote, leave it to stringLit
+		return 0, fmt.Errorf("%w: \\%c", ErrInvalidEscape, ch)
+	}
+	s.nextCh()
+	switch ch {
+	case 'n':
+		return '\n', nil
+	case 't':
+		return '\t', nil
+	case 'u':
+		return s.unicodeEscape()
+	}
+	return ch, nil
+}
+
+// unicodeEscape decodes the {XXXX} part of a \u{XXXX} escape, where XXXX
+// is the hexadecimal code of the character.
+func (s *scanner) unicodeEscape() (rune, error) {
+	if !s.accept('{') {
+		return 0, ErrInvalidUnicodeEscape
+	}
+	var code rune
+	digits := 0
+	for !s.accept('}') {
+		ch, _ := s.peekCh()
+		d := hexDigit(ch)
+		if d < 0 || digits == 6 {
+			return 0, ErrInvalidUnicodeEscape
+		}
+		s.nextCh()
+		code = code*16 + d
+		digits++
+	}
+	if digits == 0 || !utf8.ValidRune(code) {
+		return 0, ErrInvalidUnicodeEscape
 	}
+	return code, nil
 }
 
-func (s *scanner) stringLit() {
-	// skip first '"'
-	_, _ = s.nextCh()
-	lit := ""
+func hexDigit(ch rune) rune {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0'
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10
+	case 'A' <= ch && ch <= 'F':
+		return ch - 'A' + 10
+	}
+	return -1
+}
+
+// rawStringLit scans a string in backticks after the opening one. Its text
+// is taken as is: it may span lines and has no escapes or expressions.
+func (s *scanner) rawStringLit() {
+	start := s.cursor
+	for {
+		ch, _ := s.nextCh()
+		if ch == -1 {
+			s.err = ErrUnterminatedString
+			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+			return
+		}
+		if ch == '`' {
+			break
+		}
+	}
+	s.lit, s.tok = string(s.src[start:s.cursor-1]), token.STRING
+}
+
+func (s *scanner) lineComment() {
+	// "//" is already consumed
+	for {
+		ch, _ := s.peekCh()
+		if ch == '\n' || ch == -1 {
+			break
+		}
+		s.nextCh()
+	}
+	s.lit = string(s.src[s.start:s.cursor])
+	s.tok = token.COMMENT
+}
+
+func (s *scanner) blockComment() {
+	// "/*" is already consumed
 	for {
 		ch, _ := s.nextCh()
-		if ch == '"' {
+		if ch == -1 {
+			s.err = ErrUnterminatedComment
+			s.lit, s.tok = token.ILLEGAL.String(), token.ILLEGAL
+			return
+		}
+		if ch == '*' && s.accept('/') {
 			break
-		} else if ch == '\\' {
-			lit += string(ch)
-			ch, _ = s.nextCh()
 		}
-		lit += string(ch)
 	}
-	s.lit = lit
-	s.tok = token.STRING
+	s.lit = string(s.src[s.start:s.cursor])
+	s.tok = token.COMMENT
+}
+
+func (s *scanner) Dialect() token.Dialect {
+	return s.dialect
 }
 
 func (s *scanner) Err() error {
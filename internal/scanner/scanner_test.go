@@ -1,8 +1,12 @@
 package scanner_test
 
 import (
+	"errors"
+	"io"
+	"slices"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/nurtai325/qurtc/internal/scanner"
 	"github.com/nurtai325/qurtc/internal/token"
@@ -16,6 +20,7 @@ type scannerTestCase struct {
 type scannerTest struct {
 	name   string
 	input  string
+	mode   scanner.Mode
 	tokens []scannerTestCase
 }
 
@@ -45,43 +50,75 @@ var tests []scannerTest = []scannerTest{
 			{token.STRING, "text"},
 			{token.TRUE, "иә"},
 			{token.FALSE, "жоқ"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 	{
 		name:  "test IsOperator method",
-		input: "+ - * / % && || == != <= >= < > ! = ( ) [ ] { } , . ;",
+		input: "+ - * / % && || == != <= >= < > ! = ( ) [ ] { } , . : ;",
 		tokens: []scannerTestCase{
 			{token.ADD, "+"}, {token.SUB, "-"}, {token.MUL, "*"}, {token.DIV, "/"}, {token.MOD, "%"},
 			{token.LAND, "&&"}, {token.LOR, "||"}, {token.EQL, "=="}, {token.NEQ, "!="},
 			{token.LEQ, "<="}, {token.GEQ, ">="}, {token.LSS, "<"}, {token.GTR, ">"},
 			{token.NOT, "!"}, {token.ASSIGN, "="}, {token.LPAREN, "("}, {token.RPAREN, ")"},
 			{token.LBRACK, "["}, {token.RBRACK, "]"}, {token.LBRACE, "{"}, {token.RBRACE, "}"},
-			{token.COMMA, ","}, {token.PERIOD, "."}, {token.SEMICOLON, "semicolon"},
+			{token.COMMA, ","}, {token.PERIOD, "."}, {token.COLON, ":"}, {token.SEMICOLON, "semicolon"},
 			{token.EOF, "EOF"},
 		},
 	},
 	{
 		name:  "test IsKeyword method",
-		input: "тоқта өткіз әйтпесе қайтала функция егер қайтар құрылым айнымалы иә жоқ",
+		input: "тоқта өткіз әйтпесе қайтала функция егер қайтар құрылым айнымалы тұрақты таңда жағдай әдепкі иә жоқ",
 		tokens: []scannerTestCase{
 			{token.BREAK, "тоқта"}, {token.CONTINUE, "өткіз"}, {token.ELSE, "әйтпесе"},
 			{token.FOR, "қайтала"}, {token.FUNC, "функция"}, {token.IF, "егер"},
 			{token.RETURN, "қайтар"}, {token.STRUCT, "құрылым"}, {token.VAR, "айнымалы"},
-			{token.TRUE, "иә"}, {token.FALSE, "жоқ"},
+			{token.CONST, "тұрақты"}, {token.SWITCH, "таңда"}, {token.CASE, "жағдай"},
+			{token.DEFAULT, "әдепкі"}, {token.TRUE, "иә"}, {token.FALSE, "жоқ"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 
+	{
+		name:  "latin dialect is chosen by the first keyword",
+		input: "funksiia eger iä joq qūrylym tūraqty егер",
+		tokens: []scannerTestCase{
+			{token.FUNC, "funksiia"}, {token.IF, "eger"}, {token.TRUE, "iä"}, {token.FALSE, "joq"},
+			{token.STRUCT, "qūrylym"}, {token.CONST, "tūraqty"}, {token.IDENT, "егер"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "arabic dialect",
+		input: "قايتار ٴيا توقتا جوق ٴبۇتىن",
+		tokens: []scannerTestCase{
+			{token.RETURN, "قايتار"}, {token.TRUE, "ٴيا"}, {token.BREAK, "توقتا"}, {token.FALSE, "جوق"},
+			{token.IDENT, "ٴبۇتىن"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "words of other dialects are identifiers",
+		input: "eger قايتار ٴيا егер eger",
+		tokens: []scannerTestCase{
+			{token.IF, "eger"}, {token.IDENT, "قايتار"}, {token.IDENT, "ٴيا"}, {token.IDENT, "егер"}, {token.IF, "eger"},
+			{token.EOF, "EOF"},
+		},
+	},
 	{
 		name:  "boolean literals test",
-		input: "иә жоқ иәFalse жоқTrue иәжоқ",
+		input: "иә жоқ иәБа жоқТы иәжоқ",
 		tokens: []scannerTestCase{
 			{token.TRUE, "иә"},
 			{token.FALSE, "жоқ"},
-			{token.IDENT, "иәFalse"},
-			{token.IDENT, "жоқTrue"},
+			{token.IDENT, "иәБа"},
+			{token.IDENT, "жоқТы"},
 			{token.IDENT, "иәжоқ"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -116,7 +153,9 @@ var tests []scannerTest = []scannerTest{
 			{token.LBRACE, "{"},
 			{token.RETURN, "қайтар"},
 			{token.INT, "10"},
+			{token.SEMICOLON, "\n"},
 			{token.RBRACE, "}"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -125,8 +164,11 @@ var tests []scannerTest = []scannerTest{
 		input: "x\ny\nz\t",
 		tokens: []scannerTestCase{
 			{token.IDENT, "x"},
+			{token.SEMICOLON, "\n"},
 			{token.IDENT, "y"},
+			{token.SEMICOLON, "\n"},
 			{token.IDENT, "z"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -139,6 +181,7 @@ var tests []scannerTest = []scannerTest{
 			{token.INT, "00"},
 			{token.INT, "123"},
 			{token.INT, "1000000000000000000000"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -149,9 +192,11 @@ var tests []scannerTest = []scannerTest{
 			{token.FLOAT, "0.0"},
 			{token.PERIOD, "."},
 			{token.INT, "5"},
-			{token.FLOAT, "123."},
+			{token.INT, "123"},
+			{token.PERIOD, "."},
 			{token.FLOAT, "0.123"},
 			{token.FLOAT, "999.999"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -159,11 +204,11 @@ var tests []scannerTest = []scannerTest{
 		name:  "malformed float variations",
 		input: "12..45 123...456 .",
 		tokens: []scannerTestCase{
-			{token.FLOAT, "12."},
-			{token.PERIOD, "."},
+			{token.INT, "12"},
+			{token.DOTDOT, ".."},
 			{token.INT, "45"},
-			{token.FLOAT, "123."},
-			{token.PERIOD, "."},
+			{token.INT, "123"},
+			{token.DOTDOT, ".."},
 			{token.PERIOD, "."},
 			{token.INT, "456"},
 			{token.PERIOD, "."},
@@ -178,12 +223,34 @@ var tests []scannerTest = []scannerTest{
 			{token.IDENT, "abc"},
 			{token.FLOAT, "45.67"},
 			{token.IDENT, "def"},
-			{token.INT, "0"},
-			{token.IDENT, "x123"},
+			{token.INT, "0x123"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 
+	{
+		name:  "number bases, separators and exponents",
+		input: "0xFF 0X_1f 0b1010 0B1 0o17 1_000_000 1e6 2.5E-3 7e+2 1.аты 1e",
+		tokens: []scannerTestCase{
+			{token.INT, "0xFF"}, {token.INT, "0X_1f"}, {token.INT, "0b1010"}, {token.INT, "0B1"},
+			{token.INT, "0o17"}, {token.INT, "1_000_000"},
+			{token.FLOAT, "1e6"}, {token.FLOAT, "2.5E-3"}, {token.FLOAT, "7e+2"},
+			{token.INT, "1"}, {token.PERIOD, "."}, {token.IDENT, "аты"},
+			{token.INT, "1"}, {token.IDENT, "e"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "malformed numbers",
+		input: "0b102 0x 1__0 1_ 0o8 0.5_",
+		tokens: []scannerTestCase{
+			{token.ILLEGAL, "ҚАТЕ"}, {token.ILLEGAL, "ҚАТЕ"}, {token.ILLEGAL, "ҚАТЕ"},
+			{token.ILLEGAL, "ҚАТЕ"}, {token.ILLEGAL, "ҚАТЕ"}, {token.ILLEGAL, "ҚАТЕ"},
+			{token.EOF, "EOF"},
+		},
+	},
 	{
 		name:  "string variations",
 		input: "\"hello\" \"\" \"қазақша мәтін\" \"with spaces\"",
@@ -192,51 +259,119 @@ var tests []scannerTest = []scannerTest{
 			{token.STRING, ""},
 			{token.STRING, "қазақша мәтін"},
 			{token.STRING, "with spaces"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 
 	{
 		name:  "string with escape sequences",
-		input: "\"line1\nline2\" \"tab\there\" \"quote\\\"inside\"",
+		input: `"line1\nline2" "tab\there" "quote\"inside" "back\\slash" "\u{4D9}\u{1F600}"`,
 		tokens: []scannerTestCase{
 			{token.STRING, "line1\nline2"},
 			{token.STRING, "tab\there"},
-			{token.STRING, "quote\\\"inside"},
+			{token.STRING, "quote\"inside"},
+			{token.STRING, "back\\slash"},
+			{token.STRING, "ә😀"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "raw strings",
+		input: "`бірінші\\n\n{екінші}\"` ``",
+		tokens: []scannerTestCase{
+			{token.STRING, "бірінші\\n\n{екінші}\""},
+			{token.STRING, ""},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "interpolated strings",
+		input: `"Аты: {адам.аты}, {т[{1}[0]] + "{1}"}\{}" "{х}"`,
+		tokens: []scannerTestCase{
+			{token.INTERP_BEG, "Аты: "}, {token.IDENT, "адам"}, {token.PERIOD, "."}, {token.IDENT, "аты"},
+			{token.INTERP_MID, ", "}, {token.IDENT, "т"}, {token.LBRACK, "["},
+			{token.LBRACE, "{"}, {token.INT, "1"}, {token.RBRACE, "}"},
+			{token.LBRACK, "["}, {token.INT, "0"}, {token.RBRACK, "]"}, {token.RBRACK, "]"}, {token.ADD, "+"},
+			{token.INTERP_BEG, ""}, {token.INT, "1"}, {token.INTERP_END, ""},
+			{token.INTERP_END, "{}"},
+			{token.INTERP_BEG, ""}, {token.IDENT, "х"}, {token.INTERP_END, ""},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "invalid escape sequences",
+		input: `"\q" "\u{}" "\u{110000}" "\u4D9" х`,
+		tokens: []scannerTestCase{
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.IDENT, "х"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "unterminated string",
+		input: "х \"аяқталмаған\nжол",
+		tokens: []scannerTestCase{
+			{token.IDENT, "х"},
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.IDENT, "жол"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 
 	{
-		name:  "string with newlines",
+		name:  "string ends at a newline",
 		input: "\"line1\nline2\"",
 		tokens: []scannerTestCase{
-			{token.STRING, "line1\nline2"},
+			{token.ILLEGAL, "ҚАТЕ"},
+			{token.IDENT, "line2"},
+			{token.ILLEGAL, "ҚАТЕ"},
 			{token.EOF, "EOF"},
 		},
 	},
 
 	{
 		name:  "unicode identifiers",
-		input: "айнымалы сөзҰзын123 mixedАБВ кириллица",
+		input: "айнымалы сөзҰзын123 и\u0306гі latinÄğı кириллица",
 		tokens: []scannerTestCase{
 			{token.VAR, "айнымалы"},
 			{token.IDENT, "сөзҰзын123"},
-			{token.IDENT, "mixedАБВ"},
+			{token.IDENT, "йгі"},
+			{token.IDENT, "latinÄğı"},
 			{token.IDENT, "кириллица"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
 	{
 		name:  "keywords vs identifiers",
-		input: "егер егерсіз функцияFoo тоқтаBar иәFalse жоқTrue",
+		input: "егер егерсіз функцияФу тоқтаБар иәЖалған жоқШын",
 		tokens: []scannerTestCase{
 			{token.IF, "егер"},
 			{token.IDENT, "егерсіз"},
-			{token.IDENT, "функцияFoo"},
-			{token.IDENT, "тоқтаBar"},
-			{token.IDENT, "иәFalse"},
-			{token.IDENT, "жоқTrue"},
+			{token.IDENT, "функцияФу"},
+			{token.IDENT, "тоқтаБар"},
+			{token.IDENT, "иәЖалған"},
+			{token.IDENT, "жоқШын"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+
+	{
+		name:  "identifiers mixing alphabets",
+		input: "функция мысaл() жоқTrue",
+		tokens: []scannerTestCase{
+			{token.FUNC, "функция"}, {token.ILLEGAL, "ҚАТЕ"}, {token.LPAREN, "("}, {token.RPAREN, ")"},
+			{token.ILLEGAL, "ҚАТЕ"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -250,6 +385,7 @@ var tests []scannerTest = []scannerTest{
 			{token.NEQ, "!="}, {token.IDENT, "v"}, {token.LEQ, "<="}, {token.IDENT, "u"},
 			{token.GEQ, ">="}, {token.IDENT, "t"}, {token.LSS, "<"}, {token.IDENT, "s"},
 			{token.GTR, ">"}, {token.IDENT, "r"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -263,6 +399,18 @@ var tests []scannerTest = []scannerTest{
 			{token.EOF, "EOF"},
 		},
 	},
+	{
+		name:  "assignment and increment operators",
+		input: "а += 1 -= *= /= %= + +\nб++\nв--\n",
+		tokens: []scannerTestCase{
+			{token.IDENT, "а"}, {token.ADD_ASSIGN, "+="}, {token.INT, "1"}, {token.SUB_ASSIGN, "-="},
+			{token.MUL_ASSIGN, "*="}, {token.DIV_ASSIGN, "/="}, {token.MOD_ASSIGN, "%="},
+			{token.ADD, "+"}, {token.ADD, "+"},
+			{token.IDENT, "б"}, {token.INC, "++"}, {token.SEMICOLON, "\n"},
+			{token.IDENT, "в"}, {token.DEC, "--"}, {token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
 
 	{
 		name:  "single illegal character",
@@ -295,6 +443,7 @@ var tests []scannerTest = []scannerTest{
 			{token.LPAREN, "("}, {token.IDENT, "n"}, {token.SUB, "-"}, {token.INT, "1"},
 			{token.RPAREN, ")"}, {token.SEMICOLON, "semicolon"},
 			{token.RBRACE, "}"}, {token.RBRACE, "}"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -305,7 +454,9 @@ var tests []scannerTest = []scannerTest{
 			{token.STRUCT, "құрылым"}, {token.IDENT, "Point"}, {token.LBRACE, "{"},
 			{token.IDENT, "x"}, {token.IDENT, "INT"}, {token.COMMA, ","},
 			{token.IDENT, "y"}, {token.IDENT, "INT"},
+			{token.SEMICOLON, "\n"},
 			{token.RBRACE, "}"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -321,6 +472,7 @@ var tests []scannerTest = []scannerTest{
 			{token.CONTINUE, "өткіз"}, {token.SEMICOLON, "semicolon"},
 			{token.IDENT, "x"}, {token.ASSIGN, "="}, {token.IDENT, "x"}, {token.SUB, "-"},
 			{token.INT, "1"}, {token.SEMICOLON, "semicolon"}, {token.RBRACE, "}"},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -337,11 +489,73 @@ var tests []scannerTest = []scannerTest{
 		},
 	},
 
+	{
+		name:  "line and block comments are skipped",
+		input: "x // түсініктеме\n/* көп\nжолды */ y / z /**/ /* ** */",
+		tokens: []scannerTestCase{
+			{token.IDENT, "x"},
+			{token.SEMICOLON, "\n"},
+			{token.IDENT, "y"},
+			{token.DIV, "/"},
+			{token.IDENT, "z"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "comments returned in ScanComments mode",
+		input: "x // түсініктеме\n/* көп\nжолды */ y",
+		mode:  scanner.ScanComments,
+		tokens: []scannerTestCase{
+			{token.IDENT, "x"},
+			{token.SEMICOLON, "\n"},
+			{token.COMMENT, "// түсініктеме"},
+			{token.COMMENT, "/* көп\nжолды */"},
+			{token.IDENT, "y"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "comment at end of input",
+		input: "x //",
+		tokens: []scannerTestCase{
+			{token.IDENT, "x"},
+			{token.SEMICOLON, "\n"},
+			{token.EOF, "EOF"},
+		},
+	},
+	{
+		name:  "unterminated block comment",
+		input: "x /* аяқталмаған",
+		tokens: []scannerTestCase{
+			{token.IDENT, "x"},
+			{token.SEMICOLON, "\n"},
+			{token.ILLEGAL, "ҚАТЕ"},
+		},
+	},
+
+	{
+		name:  "semicolons inserted at line ends",
+		input: "х = а +\nб[0]\nф()\nқайтар\n{\n}   // түсініктеме\nтоқта /*\n*/ өткіз;\n",
+		tokens: []scannerTestCase{
+			{token.IDENT, "х"}, {token.ASSIGN, "="}, {token.IDENT, "а"}, {token.ADD, "+"},
+			{token.IDENT, "б"}, {token.LBRACK, "["}, {token.INT, "0"}, {token.RBRACK, "]"},
+			{token.SEMICOLON, "\n"},
+			{token.IDENT, "ф"}, {token.LPAREN, "("}, {token.RPAREN, ")"}, {token.SEMICOLON, "\n"},
+			{token.RETURN, "қайтар"}, {token.SEMICOLON, "\n"},
+			{token.LBRACE, "{"}, {token.RBRACE, "}"}, {token.SEMICOLON, "\n"},
+			{token.BREAK, "тоқта"}, {token.SEMICOLON, "\n"},
+			{token.CONTINUE, "өткіз"}, {token.SEMICOLON, "semicolon"},
+			{token.EOF, "EOF"},
+		},
+	},
 	{
 		name:  "very long identifier",
 		input: strings.Repeat("а", 1000),
 		tokens: []scannerTestCase{
 			{token.IDENT, strings.Repeat("а", 1000)},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -350,6 +564,7 @@ var tests []scannerTest = []scannerTest{
 		input: strings.Repeat("9", 1000),
 		tokens: []scannerTestCase{
 			{token.INT, strings.Repeat("9", 1000)},
+			{token.SEMICOLON, "\n"},
 			{token.EOF, "EOF"},
 		},
 	},
@@ -358,7 +573,7 @@ var tests []scannerTest = []scannerTest{
 func TestScanner(t *testing.T) {
 	for i, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sc := scanner.New("test.құрт", []byte(tt.input))
+			sc := scanner.New("test.құрт", []byte(tt.input), tt.mode)
 			for _, expected := range tt.tokens {
 				sc.Peek()
 				sc.Scan()
@@ -377,3 +592,199 @@ func TestScanner(t *testing.T) {
 		})
 	}
 }
+
+func TestScannerPos(t *testing.T) {
+	input := "айнымалы х бүтін;\n\tжаз(х)\n"
+	want := []token.Pos{
+		{Line: 1, Col: 1, Offset: 0},
+		{Line: 1, Col: 10, Offset: 17},
+		{Line: 1, Col: 12, Offset: 20},
+		{Line: 1, Col: 17, Offset: 30},
+		{Line: 2, Col: 2, Offset: 33},
+		{Line: 2, Col: 5, Offset: 39},
+		{Line: 2, Col: 6, Offset: 40},
+		{Line: 2, Col: 7, Offset: 42},
+		{Line: 2, Col: 8, Offset: 43},
+		{Line: 3, Col: 1, Offset: 44},
+	}
+	sc := scanner.New("test.құрт", []byte(input), 0)
+	for i, expected := range want {
+		sc.Peek()
+		sc.Scan()
+		expected.File = "test.құрт"
+		if pos := sc.Pos(); pos != expected {
+			t.Errorf("token %d (%v): got %+v, want %+v", i, sc.Tok(), pos, expected)
+		}
+	}
+}
+
+// TestScannerReader checks that reading the source byte by byte gives the
+// same tokens at the same positions as scanning it whole.
+func TestScannerReader(t *testing.T) {
+	for _, tt := range tests {
+		whole := scanner.New("test.құрт", []byte(tt.input), tt.mode)
+		read := scanner.NewReader("test.құрт", iotest.OneByteReader(strings.NewReader(tt.input)), tt.mode)
+		for {
+			whole.Scan()
+			read.Scan()
+			if read.Tok() != whole.Tok() || read.Lit() != whole.Lit() || read.Pos() != whole.Pos() || read.End() != whole.End() {
+				t.Errorf("%s: got %v %q at %v, want %v %q at %v", tt.name,
+					read.Tok(), read.Lit(), read.Pos(), whole.Tok(), whole.Lit(), whole.Pos())
+				break
+			}
+			if whole.Tok() == token.EOF {
+				break
+			}
+		}
+	}
+}
+
+func TestScannerPeekN(t *testing.T) {
+	sc := scanner.New("test.құрт", []byte("белгі# ә.ұзындық\n"), 0)
+	want := []token.Token{token.IDENT, token.ILLEGAL, token.IDENT, token.PERIOD, token.IDENT}
+	for n := len(want); n > 0; n-- {
+		if tok, _ := sc.PeekN(n); tok != want[n-1] {
+			t.Errorf("PeekN(%d): got %v, want %v", n, tok, want[n-1])
+		}
+	}
+	for i, tok := range want {
+		sc.Scan()
+		if sc.Tok() != tok {
+			t.Errorf("token %d: got %v, want %v", i, sc.Tok(), tok)
+		}
+	}
+	// the columns count letters, not bytes
+	if pos := sc.Pos(); pos.Col != 10 || pos.Offset != 15 {
+		t.Errorf("expected ұзындық at column 10, got %+v", pos)
+	}
+	if tok, _ := sc.Peek(); tok != token.SEMICOLON {
+		t.Errorf("expected a semicolon at the end of the line, got %v", tok)
+	}
+}
+
+func TestScannerReadError(t *testing.T) {
+	readErr := errors.New("диск істен шықты")
+	r := io.MultiReader(strings.NewReader("жаз(1"), iotest.ErrReader(readErr))
+	sc := scanner.NewReader("test.құрт", r, 0)
+	for sc.Scan() {
+	}
+	if !errors.Is(sc.Err(), scanner.ErrRead) || !errors.Is(sc.Err(), readErr) {
+		t.Errorf("expected %v, got %v", scanner.ErrRead, sc.Err())
+	}
+}
+
+func TestScannerInvalidUTF8(t *testing.T) {
+	sc := scanner.New("test.құрт", []byte("х \xff\xfe у"), 0)
+	var toks []token.Token
+	for range 10 {
+		sc.Scan()
+		toks = append(toks, sc.Tok())
+		if sc.Tok() == token.EOF {
+			break
+		}
+	}
+	want := []token.Token{token.IDENT, token.ILLEGAL, token.ILLEGAL, token.IDENT, token.SEMICOLON, token.EOF}
+	if !slices.Equal(toks, want) {
+		t.Errorf("got %v, want %v", toks, want)
+	}
+}
+
+func TestScannerErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+		col   int
+	}{
+		{"х = \"аяқталмаған", scanner.ErrUnterminatedString, 5},
+		{"х = \"соңы\\\"", scanner.ErrUnterminatedString, 5},
+		{"х = \"\\u{", scanner.ErrUnterminatedString, 5},
+		{"х = \"аяқталмаған\nжаз(х)\n", scanner.ErrUnterminatedString, 5},
+		{"х = \"\\a\"", scanner.ErrInvalidEscape, 5},
+		{"х = \"\\u{D800}\"", scanner.ErrInvalidUnicodeEscape, 5},
+		{"х = `аяқталмаған\n", scanner.ErrUnterminatedString, 5},
+		{"х = 0b102", scanner.ErrInvalidDigit, 5},
+		{"х = 0x;", scanner.ErrMissingDigits, 5},
+		{"х = 1__000", scanner.ErrInvalidSeparator, 5},
+		{"х = мысaл", scanner.ErrMixedScripts, 8},
+		{"х = Кiтап", scanner.ErrMixedScripts, 6},
+		{"funksiia x = bіtap", scanner.ErrMixedScripts, 15},
+		{"х = \"{а + \"{б}\"\n}", scanner.ErrUnterminatedString, 5},
+	}
+	for _, tt := range tests {
+		sc := scanner.New("test.құрт", []byte(tt.input), 0)
+		for sc.Scan() {
+		}
+		if !errors.Is(sc.Err(), tt.err) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.err, sc.Err())
+		}
+		if pos := sc.Pos(); pos.Line != 1 || pos.Col != tt.col {
+			t.Errorf("%q: expected error at 1:%d, got %v", tt.input, tt.col, pos)
+		}
+	}
+}
+
+func TestScannerMixedScriptsSuggestion(t *testing.T) {
+	sc := scanner.New("test.құрт", []byte("мысaл"), 0)
+	sc.Scan()
+	if err := sc.Err(); err == nil || !strings.Contains(err.Error(), "кирилл 'а'") {
+		t.Errorf("expected the Cyrillic а to be suggested, got %v", err)
+	}
+}
+
+// FuzzScanner checks that scanning ends on any input. Every token but an
+// inserted semicolon consumes at least one byte, so EOF must come within
+// twice the length of the input.
+func FuzzScanner(f *testing.F) {
+	for _, tt := range tests {
+		f.Add(tt.input)
+	}
+	f.Add("\"\\u{1234567")
+	f.Add("/* \"")
+	f.Fuzz(func(t *testing.T, input string) {
+		sc := scanner.New("fuzz.құрт", []byte(input), scanner.ScanComments)
+		for range 2*len(input) + 2 {
+			sc.Scan()
+			if sc.Tok() == token.EOF {
+				return
+			}
+		}
+		t.Fatalf("no EOF after %d tokens", 2*len(input)+2)
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	src := []byte("функция ештеңе негізгі() {\n\tжаз(сәлем);\n}\n")
+	start := token.Pos{Line: 2, Col: 6, Offset: 35}
+	end := token.Pos{Line: 2, Col: 11, Offset: 45}
+	want := "1 | функция ештеңе негізгі() {\n" +
+		"2 | \tжаз(сәлем);\n" +
+		"  | \t    ^~~~~\n"
+	if got := scanner.Snippet(src, start, end, 1); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	want = "2 | \tжаз(сәлем);\n" +
+		"  | \t    ^~~~~~~\n"
+	if got := scanner.Snippet(src, start, token.Pos{Line: 3, Col: 1}, 0); got != want {
+		t.Errorf("marker to the end of line: got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	pos := token.Pos{File: "test.құрт", Line: 2, Col: 6}
+	errs := scanner.ErrorList{
+		{Kind: "Синтаксис қатесі", Pos: pos, Err: scanner.ErrInvalidDigit, Snippet: "2 | жаз(1)\n", Help: "көмек"},
+		{Kind: "Тип қатесі", Pos: pos, Err: scanner.ErrInvalidSeparator},
+	}
+	want := "Синтаксис қатесі (файл: test.құрт, жол: 2, қатар: 6): " + scanner.ErrInvalidDigit.Error() + "\n" +
+		"2 | жаз(1)\nкөмек\n" +
+		"Тип қатесі (файл: test.құрт, жол: 2, қатар: 6): " + scanner.ErrInvalidSeparator.Error() + "\n"
+	if got := errs.Error(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := errs.Err(); !errors.Is(err, scanner.ErrInvalidDigit) || !errors.Is(err, scanner.ErrInvalidSeparator) {
+		t.Errorf("expected the list to wrap both errors, got %v", err)
+	}
+	if err := (scanner.ErrorList{}).Err(); err != nil {
+		t.Errorf("expected nil for an empty list, got %v", err)
+	}
+}
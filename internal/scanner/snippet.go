@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+// Snippet renders the source line of start with a ^~~~ marker under the
+// range [start, end), preceded by up to context lines before it:
+//
+//	3 |     айнымалы б бүтін = 5
+//	4 |     жаз(а);
+//	  |     ^~~
+//
+// end may be on a later line, then the marker runs to the end of the line.
+func Snippet(src []byte, start, end token.Pos, context int) string {
+	if !start.IsValid() {
+		return ""
+	}
+	lines := bytes.Split(src, []byte("\n"))
+	if start.Line > len(lines) {
+		return ""
+	}
+	first := max(start.Line-context, 1)
+	gutter := len(fmt.Sprint(start.Line))
+
+	var b strings.Builder
+	for n := first; n <= start.Line; n++ {
+		line := bytes.TrimRight(lines[n-1], "\r")
+		fmt.Fprintf(&b, "%*d | %s\n", gutter, n, line)
+	}
+
+	line := bytes.TrimRight(lines[start.Line-1], "\r")
+	var prefix []byte
+	for i, r := 0, 1; r < start.Col && i < len(line); r++ {
+		ch, size := utf8.DecodeRune(line[i:])
+		if ch == '\t' {
+			prefix = append(prefix, '\t')
+		} else {
+			prefix = append(prefix, ' ')
+		}
+		i += size
+	}
+	width := utf8.RuneCount(line) - start.Col + 1
+	if end.Line == start.Line && end.Col > start.Col {
+		width = min(width, end.Col-start.Col)
+	}
+	fmt.Fprintf(&b, "%*s | %s^%s\n", gutter, "", prefix, strings.Repeat("~", max(width-1, 0)))
+	return b.String()
+}
+
+func (s *scanner) Snippet(start, end token.Pos, context int) string {
+	return Snippet(s.src, start, end, context)
+}
+
+// Error is an error at a range of the source, like a syntax error or a type
+// error. The parser and the checker report them with a Snippet.
+type Error struct {
+	Kind    string // the kind of the error, like "Синтаксис қатесі"
+	Pos     token.Pos
+	End     token.Pos
+	Err     error
+	Snippet string // source lines with the error marked, may be empty
+	Help    string // a line after the snippet, may be empty
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (файл: %s, жол: %d, қатар: %d): %s\n", e.Kind, e.Pos.File, e.Pos.Line, e.Pos.Col, e.Err.Error())
+	b.WriteString(e.Snippet)
+	if e.Help != "" {
+		b.WriteString(e.Help + "\n")
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is a list of errors in the order they appear in the file.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	var b strings.Builder
+	for _, err := range l {
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, 0, len(l))
+	for _, err := range l {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Err returns nil for an empty list so that it can be returned as an error.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
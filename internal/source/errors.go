@@ -0,0 +1,8 @@
+package source
+
+import "errors"
+
+var (
+	ErrUnknownEncoding = errors.New("файлдың кодтауын анықтау мүмкін болмады, файлды UTF-8 кодтауымен сақтаңыз")
+	ErrInvalidUTF16    = errors.New("файл UTF-16 кодтауымен сақталған, бірақ ішінде қате таңбалар бар")
+)
@@ -0,0 +1,117 @@
+// Package source turns the bytes of a file into the UTF-8 text with '\n'
+// line endings the scanner expects. School computers often run Windows,
+// where editors save files with a byte order mark, CRLF line endings or in
+// the Windows-1251 and KZ-1048 code pages.
+package source
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	utf16 "golang.org/x/text/encoding/unicode"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// kz1048 are the letters of KZ-1048 that differ from Windows-1251, the rest
+// of the code page is the same.
+var kz1048 = map[byte]rune{
+	0x8D: 'Қ', 0x8E: 'Һ', 0x9D: 'қ', 0x9E: 'һ',
+	0xA1: 'Ұ', 0xA2: 'ұ', 0xA3: 'Ә', 0xA5: 'Ө', 0xAA: 'Ғ', 0xAF: 'Ү',
+	0xB4: 'ө', 0xBA: 'ғ', 0xBC: 'ә', 0xBD: 'Ң', 0xBE: 'ң', 0xBF: 'ү',
+}
+
+// Decode returns src as UTF-8 without a byte order mark and with '\n' line
+// endings.
+func Decode(src []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(src, utf16LEBOM), bytes.HasPrefix(src, utf16BEBOM):
+		decoded, err := utf16.UTF16(utf16.BigEndian, utf16.ExpectBOM).NewDecoder().Bytes(src)
+		if err != nil || !utf8.Valid(decoded) || bytes.ContainsRune(decoded, utf8.RuneError) {
+			return nil, ErrInvalidUTF16
+		}
+		src = decoded
+	case utf8.Valid(src):
+	default:
+		var err error
+		if src, err = decodeLegacy(src); err != nil {
+			return nil, err
+		}
+	}
+	src = bytes.TrimPrefix(src, utf8BOM)
+	if bytes.ContainsFunc(src, isBinary) {
+		// UTF-16 without a byte order mark or not a text file at all
+		return nil, ErrUnknownEncoding
+	}
+	return normalizeNewlines(src), nil
+}
+
+// decodeLegacy decodes src from KZ-1048 if it has any of the Kazakh
+// letters of the code page, otherwise from Windows-1251. Most letters of
+// a program in either code page are Cyrillic, so src is in some other
+// encoding if they are not.
+func decodeLegacy(src []byte) ([]byte, error) {
+	kazakh := false
+	for _, b := range src {
+		if _, ok := kz1048[b]; ok {
+			kazakh = true
+			break
+		}
+	}
+	out := make([]byte, 0, len(src)*2)
+	letters, others := 0, 0
+	for _, b := range src {
+		if b < utf8.RuneSelf {
+			out = append(out, b)
+			continue
+		}
+		r, ok := kz1048[b]
+		if !ok || !kazakh {
+			r = charmap.Windows1251.DecodeByte(b)
+		}
+		if r == utf8.RuneError {
+			return nil, ErrUnknownEncoding
+		}
+		if unicode.Is(unicode.Cyrillic, r) {
+			letters++
+		} else {
+			others++
+		}
+		out = utf8.AppendRune(out, r)
+	}
+	if letters <= others {
+		return nil, ErrUnknownEncoding
+	}
+	return out, nil
+}
+
+// isBinary reports whether r is a control character that can not be in a
+// program.
+func isBinary(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' && r != '\f'
+}
+
+// normalizeNewlines replaces "\r\n" and lone '\r' with '\n'.
+func normalizeNewlines(src []byte) []byte {
+	if bytes.IndexByte(src, '\r') < 0 {
+		return src
+	}
+	out := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		if src[i] != '\r' {
+			out = append(out, src[i])
+			continue
+		}
+		out = append(out, '\n')
+		if i+1 < len(src) && src[i+1] == '\n' {
+			i++
+		}
+	}
+	return out
+}
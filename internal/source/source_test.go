@@ -0,0 +1,68 @@
+package source_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nurtai325/qurtc/internal/source"
+	"github.com/nurtai325/qurtc/internal/testutils"
+)
+
+func TestDecodeExamples(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		got, err := source.Decode(contents)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(got, contents) {
+			t.Errorf("%s changed after decoding", name)
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"utf-8 bom", []byte("\xEF\xBB\xBFжаз(1)\n"), "жаз(1)\n"},
+		{"crlf", []byte("а\r\nб\rв\n"), "а\nб\nв\n"},
+		{"utf-16 le", []byte("\xFF\xFE\x36\x04\x30\x04\x0D\x00\x0A\x00"), "жа\n"},
+		{"utf-16 be", []byte("\xFE\xFF\x04\x36\x04\x30"), "жа"},
+		// жаз("сәлем")
+		{"kz-1048", []byte("\xE6\xE0\xE7(\"\xF1\xBC\xEB\xE5\xEC\")\r\n"), "жаз(\"сәлем\")\n"},
+		// жаз("привет")
+		{"windows-1251", []byte("\xE6\xE0\xE7(\"\xEF\xF0\xE8\xE2\xE5\xF2\")"), "жаз(\"привет\")"},
+	}
+	for _, tt := range tests {
+		got, err := source.Decode(tt.input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"utf-16 without bom", []byte("\x36\x04\x30\x04"), source.ErrUnknownEncoding},
+		{"binary", []byte("\x00\x01\x02\x03"), source.ErrUnknownEncoding},
+		{"not letters", []byte("\x82\x84\x85 \xE0"), source.ErrUnknownEncoding},
+		{"undefined byte", []byte("\xE0\x98"), source.ErrUnknownEncoding},
+		{"broken utf-16", []byte("\xFF\xFE\x00\xD8"), source.ErrInvalidUTF16},
+	}
+	for _, tt := range tests {
+		if _, err := source.Decode(tt.input); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+}
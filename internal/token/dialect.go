@@ -0,0 +1,115 @@
+package token
+
+// Dialect is the script the keywords of a program are written in: Kazakh is
+// written in Cyrillic, in the Latin alphabet of 2021 and in the Arabic based
+// төте жазу. Each file uses one dialect, the scanner picks it by the first
+// keyword of the file.
+type Dialect int
+
+const (
+	Cyrillic Dialect = iota
+	Latin
+	Arabic
+)
+
+var dialects = [...]string{
+	Cyrillic: "кирилл",
+	Latin:    "латын",
+	Arabic:   "төте",
+}
+
+func (d Dialect) String() string {
+	return dialects[d]
+}
+
+// ParseDialect returns the dialect called name.
+func ParseDialect(name string) (Dialect, bool) {
+	for d, dialectName := range dialects {
+		if name == dialectName {
+			return Dialect(d), true
+		}
+	}
+	return 0, false
+}
+
+// spellings are the words of the Latin and Arabic dialects, the Cyrillic
+// ones are in tokens.
+var spellings = [...]map[Token]string{
+	Latin: {
+		TRUE:  "iä",
+		FALSE: "joq",
+
+		BREAK:    "toqta",
+		CONTINUE: "ötkız",
+		ELSE:     "äitpese",
+		FOR:      "qaitala",
+		SWITCH:   "tañda",
+		CASE:     "jağdai",
+		DEFAULT:  "ädepkı",
+		FUNC:     "funksiia",
+		IF:       "eger",
+		RETURN:   "qaitar",
+		STRUCT:   "qūrylym",
+		VAR:      "ainymaly",
+		CONST:    "tūraqty",
+	},
+	Arabic: {
+		TRUE:  "ٴيا",
+		FALSE: "جوق",
+
+		BREAK:    "توقتا",
+		CONTINUE: "وتكىز",
+		ELSE:     "ايتپەسە",
+		FOR:      "قايتالا",
+		SWITCH:   "تاڭدا",
+		CASE:     "جاعداي",
+		DEFAULT:  "ادەپكى",
+		FUNC:     "فۋنكتسييا",
+		IF:       "ەگەر",
+		RETURN:   "قايتار",
+		STRUCT:   "قۇرىلىم",
+		VAR:      "اينىمالى",
+		CONST:    "تۇراقتى",
+	},
+}
+
+// In returns how t is written in dialect d. Only keywords, иә and жоқ
+// differ between dialects.
+func (t Token) In(d Dialect) string {
+	if word, ok := spellings[d][t]; ok {
+		return word
+	}
+	return t.String()
+}
+
+// words maps the keywords, иә and жоқ of each dialect to their tokens.
+var words [len(dialects)]map[string]Token
+
+func init() {
+	for d := range words {
+		words[d] = make(map[string]Token)
+		for _, tok := range []Token{TRUE, FALSE} {
+			words[d][tok.In(Dialect(d))] = tok
+		}
+		for tok := keyword_beg + 1; tok < keyword_end; tok++ {
+			words[d][tok.In(Dialect(d))] = tok
+		}
+	}
+}
+
+// LookupIn returns the keyword, token.TRUE or token.FALSE ident is in
+// dialect d.
+func LookupIn(ident string, d Dialect) (Token, bool) {
+	tok, ok := words[d][ident]
+	return tok, ok
+}
+
+// DetectDialect returns the dialect ident is a keyword of.
+func DetectDialect(ident string) (Dialect, bool) {
+	for d := range words {
+		if tok, ok := words[d][ident]; ok && tok.IsKeyword() {
+			return Dialect(d), true
+		}
+	}
+	return 0, false
+}
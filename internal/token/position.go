@@ -0,0 +1,23 @@
+package token
+
+import "fmt"
+
+// Pos is a position in a source file. Line and Col start from 1, Col counts
+// runes rather than bytes, Offset is the byte offset from the start of the file.
+type Pos struct {
+	File   string `json:"-"`
+	Line   int    `json:"line"`
+	Col    int    `json:"col"`
+	Offset int    `json:"offset"`
+}
+
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
@@ -5,14 +5,20 @@ type Token int
 const (
 	ILLEGAL Token = iota
 	EOF
+	COMMENT // /* түсініктеме */
 
 	literal_beg
 	IDENT  // main
 	INT    // 12345
 	FLOAT  // 123.45
 	STRING // "abc"
-	TRUE   // иә
-	FALSE  // жоқ
+
+	INTERP_BEG // "abc{
+	INTERP_MID // }abc{
+	INTERP_END // }abc"
+
+	TRUE  // иә
+	FALSE // жоқ
 	literal_end
 
 	operator_beg
@@ -34,6 +40,15 @@ const (
 	NEQ // !=
 	LEQ // <=
 	GEQ // >=
+
+	ADD_ASSIGN // +=
+	SUB_ASSIGN // -=
+	MUL_ASSIGN // *=
+	DIV_ASSIGN // /=
+	MOD_ASSIGN // %=
+
+	INC // ++
+	DEC // --
 	operator_end
 
 	LPAREN // (
@@ -41,6 +56,8 @@ const (
 	LBRACE // {
 	COMMA  // ,
 	PERIOD // .
+	DOTDOT // ..
+	COLON  // :
 
 	RPAREN    // )
 	RBRACK    // ]
@@ -54,25 +71,36 @@ const (
 	ELSE // әйтпесе
 	FOR  // қайтала
 
+	SWITCH  // таңда
+	CASE    // жағдай
+	DEFAULT // әдепкі
+
 	FUNC   // функция
 	IF     // егер
 	RETURN // қайтар
 
 	STRUCT // құрылым
 	VAR    // айнымалы
+	CONST  // тұрақты
 	keyword_end
 )
 
 var tokens = [...]string{
 	ILLEGAL: "ҚАТЕ",
 	EOF:     "EOF",
+	COMMENT: "ТҮСІНІКТЕМЕ",
 
 	IDENT:  "АТАУ",
 	INT:    "БҮТІН",
 	FLOAT:  "БӨЛШЕК",
 	STRING: "ЖОЛ",
-	TRUE:   "иә",
-	FALSE:  "жоқ",
+
+	INTERP_BEG: "ЖОЛ_БАСЫ",
+	INTERP_MID: "ЖОЛ_ОРТАСЫ",
+	INTERP_END: "ЖОЛ_СОҢЫ",
+
+	TRUE:  "иә",
+	FALSE: "жоқ",
 
 	ADD: "+",
 	SUB: "-",
@@ -93,11 +121,22 @@ var tokens = [...]string{
 	LEQ: "<=",
 	GEQ: ">=",
 
+	ADD_ASSIGN: "+=",
+	SUB_ASSIGN: "-=",
+	MUL_ASSIGN: "*=",
+	DIV_ASSIGN: "/=",
+	MOD_ASSIGN: "%=",
+
+	INC: "++",
+	DEC: "--",
+
 	LPAREN: "(",
 	LBRACK: "[",
 	LBRACE: "{",
 	COMMA:  ",",
 	PERIOD: ".",
+	DOTDOT: "..",
+	COLON:  ":",
 
 	RPAREN:    ")",
 	RBRACK:    "]",
@@ -110,6 +149,10 @@ var tokens = [...]string{
 	ELSE: "әйтпесе",
 	FOR:  "қайтала",
 
+	SWITCH:  "таңда",
+	CASE:    "жағдай",
+	DEFAULT: "әдепкі",
+
 	FUNC: "функция",
 	IF:   "егер",
 
@@ -117,6 +160,7 @@ var tokens = [...]string{
 
 	STRUCT: "құрылым",
 	VAR:    "айнымалы",
+	CONST:  "тұрақты",
 }
 
 func (t Token) String() string {
@@ -138,6 +182,16 @@ func Lookup(ident string) (Token, bool) {
 	return 0, false
 }
 
+// LookupOperator returns the operator written as op.
+func LookupOperator(op string) (Token, bool) {
+	for tok := operator_beg + 1; tok < operator_end; tok++ {
+		if tokens[tok] == op {
+			return tok, true
+		}
+	}
+	return 0, false
+}
+
 func (t Token) IsLiteral() bool { return literal_beg < t && t < literal_end }
 
 func (t Token) IsOperator() bool { return operator_beg < t && t < operator_end }
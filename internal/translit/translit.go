@@ -0,0 +1,59 @@
+// Package translit converts programs between the keyword dialects.
+package translit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+var ErrConflict = errors.New("бұл атау басқа жазудың сөзімен бірдей, оны өзгертпей аудару мүмкін емес")
+
+// Translit rewrites the keywords, иә, жоқ and primitive type names of src in
+// dialect to. Identifiers, literals, comments and spacing are kept byte for
+// byte, so converting the result back gives src again.
+func Translit(filename string, src []byte, to token.Dialect) ([]byte, error) {
+	s := scanner.New(filename, src, scanner.ScanComments)
+	var out bytes.Buffer
+	last := 0
+	for {
+		s.Scan()
+		tok := s.Tok()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.ILLEGAL {
+			return nil, fmt.Errorf("%s: %w", s.Pos(), s.Err())
+		}
+		from := s.Dialect()
+		var word string
+		switch {
+		case tok.IsKeyword(), tok == token.TRUE, tok == token.FALSE:
+			word = tok.In(to)
+		case tok == token.IDENT:
+			if kind := ast.GetKind(s.Lit(), from); kind != ast.TStruct {
+				word = kind.In(to)
+				break
+			}
+			// a name that is a word in the other dialect would change
+			// meaning after the conversion
+			_, isWord := token.LookupIn(s.Lit(), to)
+			if isWord || ast.GetKind(s.Lit(), to) != ast.TStruct {
+				return nil, fmt.Errorf("%s: %w: %s", s.Pos(), ErrConflict, s.Lit())
+			}
+			continue
+		default:
+			continue
+		}
+		start, end := s.Pos().Offset, s.End().Offset
+		out.Write(src[last:start])
+		out.WriteString(word)
+		last = end
+	}
+	out.Write(src[last:])
+	return out.Bytes(), nil
+}
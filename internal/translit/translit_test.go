@@ -0,0 +1,50 @@
+package translit_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/testutils"
+	"github.com/nurtai325/qurtc/internal/token"
+	"github.com/nurtai325/qurtc/internal/translit"
+)
+
+func TestTranslitRoundTrip(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		for _, dialect := range []token.Dialect{token.Latin, token.Arabic} {
+			converted, err := translit.Translit(name, contents, dialect)
+			if err != nil {
+				t.Fatalf("%s to %s: %v", name, dialect, err)
+			}
+			if _, err := parser.New(name, converted).Parse(); err != nil {
+				t.Errorf("%s in %s does not parse:\n%v", name, dialect, err)
+			}
+			back, err := translit.Translit(name, converted, token.Cyrillic)
+			if err != nil {
+				t.Fatalf("%s from %s: %v", name, dialect, err)
+			}
+			if !bytes.Equal(back, contents) {
+				t.Errorf("%s changed after converting to %s and back:\n%s", name, dialect, back)
+			}
+		}
+	})
+}
+
+func TestTranslit(t *testing.T) {
+	src := "функция ештеңе негізгі() {\n\tайнымалы а шын = иә // жоқ\n\tжаз(\"егер\")\n}\n"
+	want := "funksiia eşteñe негізгі() {\n\tainymaly а şyn = iä // жоқ\n\tжаз(\"егер\")\n}\n"
+	got, err := translit.Translit("test.құрт", []byte(src), token.Latin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	src = "функция ештеңе негізгі() {\n\tайнымалы eger бүтін = 1\n}\n"
+	if _, err := translit.Translit("test.құрт", []byte(src), token.Latin); !errors.Is(err, translit.ErrConflict) {
+		t.Errorf("expected %v, got %v", translit.ErrConflict, err)
+	}
+}
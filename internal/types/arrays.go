@@ -3,12 +3,11 @@ package types
 func NewArray(elements []Type) (*Array, error) {
 	return &Array{
 		elements: elements,
-		length:   len(elements),
 	}, nil
 }
 
 func (a *Array) Len() int {
-	return a.length
+	return len(a.elements)
 }
 
 func (a *Array) Get(i int) (Type, error) {
@@ -28,8 +27,38 @@ func (a *Array) Set(i int, val Type) error {
 	return nil
 }
 
+// Append adds vals to the end of a list.
+func (a *Array) Append(vals ...Type) error {
+	for _, val := range vals {
+		if len(a.elements) > 0 && !IsSameType(a.elements[0], val) {
+			return ErrNotSameType
+		}
+		a.elements = append(a.elements, val)
+	}
+	return nil
+}
+
+// Remove removes the element at i from a list, the elements after it move
+// one place to the front.
+func (a *Array) Remove(i int) error {
+	if a.isOutOfBound(i) {
+		return ErrOutOfBound
+	}
+	a.elements = append(a.elements[:i], a.elements[i+1:]...)
+	return nil
+}
+
+// Slice returns a new list of the elements from i up to j, not including j.
+// Changing the new list does not change a.
+func (a *Array) Slice(i, j int) (*Array, error) {
+	if i < 0 || j > len(a.elements) || i > j {
+		return nil, ErrSliceBounds
+	}
+	return NewArray(append([]Type(nil), a.elements[i:j]...))
+}
+
 func (a *Array) isOutOfBound(i int) bool {
-	if a.length <= i || i < 0 {
+	if len(a.elements) <= i || i < 0 {
 		return true
 	}
 	return false
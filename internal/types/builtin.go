@@ -0,0 +1,127 @@
+package types
+
+import (
+	"github.com/nurtai325/qurtc/internal/ast"
+)
+
+// builtinFuncs are the functions provided by the machine. They are given
+// the types of the arguments, already checked, and return the type of the
+// result or nil after reporting an error. A function of the program with
+// the same name is called instead of a builtin.
+var builtinFuncs = map[string]func(c *checker, call *ast.CallExpr, args []*ast.Type) *ast.Type{
+	// жаз takes any number of arguments of any type
+	"жаз": func(*checker, *ast.CallExpr, []*ast.Type) *ast.Type {
+		return primitive(ast.TVoid)
+	},
+	"ұзындық": (*checker).builtinLen,
+	"қос":     (*checker).builtinAppend,
+	"өшір":    (*checker).builtinRemove,
+	"бөлік":   (*checker).builtinSlice,
+	"бар":     (*checker).builtinHas,
+}
+
+// ұзындық(т) is the number of elements of an array or a list, the number
+// of keys of a map or the number of letters of a string.
+func (c *checker) builtinLen(call *ast.CallExpr, args []*ast.Type) *ast.Type {
+	if !c.argCount(call, 1) {
+		return nil
+	}
+	if !args[0].IsArray && !isKind(args[0], ast.TMap) && !isKind(args[0], ast.TString) {
+		c.errorf(call.Args[0], "%w: %s", ErrLenArg, typeString(args[0]))
+		return nil
+	}
+	return primitive(ast.TInt)
+}
+
+// қос(т, а, б) adds а and б to the end of the list т.
+func (c *checker) builtinAppend(call *ast.CallExpr, args []*ast.Type) *ast.Type {
+	if len(args) < 2 {
+		c.errorf(call, "%w: қос функциясы кемінде 2 аргумент алады, бірақ %d берілген", ErrArgCount, len(args))
+		return nil
+	}
+	if !args[0].IsList() {
+		c.errorf(call.Args[0], "%w: %s", ErrNotList, typeString(args[0]))
+		return nil
+	}
+	if !c.changed(call.Args[0]) {
+		return nil
+	}
+	for i, arg := range call.Args[1:] {
+		c.assignable(arg, args[i+1], elemType(args[0]))
+	}
+	return primitive(ast.TVoid)
+}
+
+// өшір(т, и) removes the element at и from the list т, өшір(с, к) removes
+// the key к from the map с.
+func (c *checker) builtinRemove(call *ast.CallExpr, args []*ast.Type) *ast.Type {
+	if !c.argCount(call, 2) {
+		return nil
+	}
+	switch {
+	case args[0].IsList():
+		c.index(call.Args[1], args[1])
+	case isKind(args[0], ast.TMap):
+		c.assignable(call.Args[1], args[1], args[0].Key)
+	default:
+		c.errorf(call.Args[0], "%w: %s", ErrRemoveArg, typeString(args[0]))
+		return nil
+	}
+	c.changed(call.Args[0])
+	return primitive(ast.TVoid)
+}
+
+// бөлік(т, басы, соңы) is a new list of the elements of т from басы up to
+// соңы, not including соңы.
+func (c *checker) builtinSlice(call *ast.CallExpr, args []*ast.Type) *ast.Type {
+	if !c.argCount(call, 3) {
+		return nil
+	}
+	if !args[0].IsArray {
+		c.errorf(call.Args[0], "%w: %s", ErrSliceNotArray, typeString(args[0]))
+		return nil
+	}
+	c.index(call.Args[1], args[1])
+	c.index(call.Args[2], args[2])
+	list := *args[0]
+	list.ArrayLen = 0
+	return &list
+}
+
+func (c *checker) argCount(call *ast.CallExpr, n int) bool {
+	if len(call.Args) == n {
+		return true
+	}
+	name := call.Func.(*ast.NameExpr).Value
+	c.errorf(call, "%w: %s функциясы %d аргумент алады, бірақ %d берілген", ErrArgCount, name, n, len(call.Args))
+	return false
+}
+
+// бар(с, к) reports whether the map с has the key к.
+func (c *checker) builtinHas(call *ast.CallExpr, args []*ast.Type) *ast.Type {
+	if !c.argCount(call, 2) {
+		return nil
+	}
+	if !isKind(args[0], ast.TMap) {
+		c.errorf(call.Args[0], "%w: %s", ErrNotMap, typeString(args[0]))
+		return nil
+	}
+	c.assignable(call.Args[1], args[1], args[0].Key)
+	return primitive(ast.TBool)
+}
+
+// changed reports whether the list or map expr can be changed by қос and
+// өшір, constants cannot.
+func (c *checker) changed(expr ast.Expr) bool {
+	if name := ast.AssignedVar(expr); name != nil && c.env.isConst(name.Value) {
+		c.errorf(expr, "%w: %s", ErrAssignToConst, name.Value)
+		return false
+	}
+	return true
+}
+
+func (c *checker) index(expr ast.Expr, typ *ast.Type) {
+	if !isKind(typ, ast.TInt) {
+		c.errorf(expr, "%w: %s", ErrIndexNotInt, typeString(typ))
+	}
+}
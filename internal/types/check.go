@@ -0,0 +1,872 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/nurtai325/qurtc/internal/ast"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/token"
+)
+
+type (
+	// Error is a problem found by Check at a position in the source.
+	Error = scanner.Error
+	// ErrorList is a list of errors in the order they appear in the file.
+	ErrorList = scanner.ErrorList
+)
+
+type checker struct {
+	src      []byte
+	structs  map[string]*ast.StructDecl
+	funcs    map[string]*ast.FuncDecl
+	errs     ErrorList
+	env      *env
+	globals  *env          // global variables, the parent of function scopes
+	fn       *ast.FuncDecl // function being checked
+	loops    int           // number of loops around the current statement
+	switches int           // number of таңда around the current statement
+	labels   []string      // labels of the loops around the current statement
+}
+
+// env holds the variables declared in a block.
+type env struct {
+	vars   map[string]*ast.Type
+	consts map[string]ast.Expr // names in vars declared with тұрақты and their values
+	parent *env
+}
+
+// owner returns the env name is declared in, or nil.
+func (e *env) owner(name string) *env {
+	for ; e != nil; e = e.parent {
+		if _, ok := e.vars[name]; ok {
+			return e
+		}
+	}
+	return nil
+}
+
+func (e *env) lookup(name string) *ast.Type {
+	if owner := e.owner(name); owner != nil {
+		return owner.vars[name]
+	}
+	return nil
+}
+
+func (e *env) isConst(name string) bool {
+	if owner := e.owner(name); owner != nil {
+		_, ok := owner.consts[name]
+		return ok
+	}
+	return false
+}
+
+// Check resolves names and checks the types of the whole program before it
+// runs. Every problem found is reported in the returned ErrorList. src is
+// used to show the offending lines and may be nil.
+func Check(src []byte, decls []ast.Decl) error {
+	c := checker{
+		src:     src,
+		structs: make(map[string]*ast.StructDecl),
+		funcs:   make(map[string]*ast.FuncDecl),
+		globals: &env{vars: make(map[string]*ast.Type)},
+	}
+	for _, decl := range decls {
+		switch v := decl.(type) {
+		case *ast.StructDecl:
+			if _, ok := c.structs[v.Name.Value]; ok {
+				c.errorf(v.Name, "%w: %s", ErrDuplicateStruct, v.Name.Value)
+				continue
+			}
+			c.structs[v.Name.Value] = v
+		case *ast.FuncDecl:
+			// the other builtins came later and can be hidden by the
+			// functions of a program, like қос in the tour
+			if _, ok := c.funcs[v.Name.Value]; ok || v.Name.Value == "жаз" {
+				c.errorf(v.Name, "%w: %s", ErrDuplicateFunc, v.Name.Value)
+				continue
+			}
+			c.funcs[v.Name.Value] = v
+		}
+	}
+	// globals are initialized in order before негізгі runs, so an
+	// initializer only sees the globals declared above it
+	c.env = c.globals
+	for _, decl := range decls {
+		if v, ok := decl.(*ast.VarDecl); ok {
+			c.stmt(v.Var)
+		}
+	}
+	c.env = nil
+	for _, decl := range decls {
+		switch v := decl.(type) {
+		case *ast.StructDecl:
+			c.structDecl(v)
+		case *ast.FuncDecl:
+			c.funcDecl(v)
+		}
+	}
+	slices.SortStableFunc(c.errs, func(a, b *Error) int {
+		return a.Pos.Offset - b.Pos.Offset
+	})
+	return c.errs.Err()
+}
+
+func (c *checker) errorf(node ast.Node, format string, args ...any) {
+	pos, end := node.Pos(), node.End()
+	c.errs = append(c.errs, &Error{
+		Kind:    "Тип қатесі",
+		Pos:     pos,
+		End:     end,
+		Err:     fmt.Errorf(format, args...),
+		Snippet: scanner.Snippet(c.src, pos, end, 1),
+	})
+}
+
+func (c *checker) structDecl(decl *ast.StructDecl) {
+	seen := make(map[string]bool, len(decl.Fields))
+	for _, field := range decl.Fields {
+		if seen[field.Name] {
+			c.errorf(field.Type, "%w: %s", ErrDuplicateField, field.Name)
+		}
+		seen[field.Name] = true
+		c.validType(field.Type)
+	}
+	if c.containsStruct(decl, decl.Name.Value, make(map[string]bool)) {
+		c.errorf(decl.Name, "%w: %s", ErrRecursiveStruct, decl.Name.Value)
+	}
+}
+
+// containsStruct reports whether decl has a field of struct type name,
+// directly or through the fields of other structs.
+func (c *checker) containsStruct(decl *ast.StructDecl, name string, visited map[string]bool) bool {
+	if visited[decl.Name.Value] {
+		return false
+	}
+	visited[decl.Name.Value] = true
+	for _, field := range decl.Fields {
+		// an empty list is the zero value of a list, so a struct can
+		// have a list of itself
+		if field.Type.Kind != ast.TStruct || field.Type.IsList() {
+			continue
+		}
+		if field.Type.Name.Value == name {
+			return true
+		}
+		if inner, ok := c.structs[field.Type.Name.Value]; ok && c.containsStruct(inner, name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) funcDecl(decl *ast.FuncDecl) {
+	if !isVoid(decl.ReturnType) {
+		c.validType(decl.ReturnType)
+	}
+	c.fn = decl
+	c.env = &env{vars: make(map[string]*ast.Type, len(decl.Args)), parent: c.globals}
+	for _, arg := range decl.Args {
+		if c.validType(arg.Type) {
+			c.declare(arg.Type, arg.Name, arg.Type)
+		}
+	}
+	c.stmts(decl.Body)
+	if !isVoid(decl.ReturnType) && !terminates(ast.Stmts(decl.Body)) {
+		// point to the closing brace of the body
+		end := decl.End()
+		brace := end
+		brace.Col, brace.Offset = end.Col-1, end.Offset-1
+		c.errorf(&ast.Span{StartPos: brace, EndPos: end}, "%w: %s", ErrMissingReturn, decl.Name.Value)
+	}
+	c.env, c.fn = nil, nil
+}
+
+// terminates reports whether stmt always ends with a қайтар, so that the
+// statements after it can never run.
+func terminates(stmt ast.Stmt) bool {
+	switch v := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case ast.Stmts:
+		return len(v) != 0 && terminates(v[len(v)-1])
+	case *ast.IfStmt:
+		return v.Else != nil && terminates(ast.Stmts(v.Then)) && terminates(v.Else)
+	case *ast.ForStmt:
+		return v.Cond == nil && !hasBreak(v.Body, "", false)
+	case *ast.LabeledStmt:
+		loop, ok := v.Stmt.(*ast.ForStmt)
+		return ok && loop.Cond == nil && !hasBreak(loop.Body, v.Label.Value, false)
+	case *ast.SwitchStmt:
+		hasDefault := false
+		for _, clause := range v.Cases {
+			if !terminates(ast.Stmts(clause.Body)) || hasBreak(clause.Body, "", false) {
+				return false
+			}
+			hasDefault = hasDefault || clause.Values == nil
+		}
+		return hasDefault
+	default:
+		return false
+	}
+}
+
+// hasBreak reports whether stmts contain a тоқта of the loop labelled label
+// they belong to, label is "" for a loop without one. nested is true in the
+// loops and the таңда inside the loop, where only тоқта label ends it.
+func hasBreak(stmts []ast.Stmt, label string, nested bool) bool {
+	for _, stmt := range stmts {
+		switch v := stmt.(type) {
+		case *ast.BreakStmt:
+			if v.Label == nil && !nested || v.Label != nil && v.Label.Value == label {
+				return true
+			}
+		case *ast.IfStmt:
+			if hasBreak(v.Then, label, nested) || (v.Else != nil && hasBreak([]ast.Stmt{v.Else}, label, nested)) {
+				return true
+			}
+		case ast.Stmts:
+			if hasBreak(v, label, nested) {
+				return true
+			}
+		case *ast.LabeledStmt:
+			if hasBreak([]ast.Stmt{v.Stmt}, label, nested) {
+				return true
+			}
+		case *ast.ForStmt:
+			if label != "" && hasBreak(v.Body, label, true) {
+				return true
+			}
+		case *ast.ForEachStmt:
+			if label != "" && hasBreak(v.Body, label, true) {
+				return true
+			}
+		case *ast.SwitchStmt:
+			for _, clause := range v.Cases {
+				if hasBreak(clause.Body, label, true) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// validType reports whether typ is a known type a value can have.
+func (c *checker) validType(typ *ast.Type) bool {
+	switch typ.Kind {
+	case ast.TVoid:
+		c.errorf(typ, "%w: %s", ErrVoidValue, typeString(typ))
+		return false
+	case ast.TStruct:
+		if _, ok := c.structs[typ.Name.Value]; !ok {
+			c.errorf(typ, "%w: %s", ErrUnknownType, typ.Name.Value)
+			return false
+		}
+	case ast.TMap:
+		if typ.Key == nil || typ.Value == nil {
+			c.errorf(typ, "%w: %s", ErrUnknownType, typ.Name.Value)
+			return false
+		}
+		if !c.validType(typ.Key) || !c.validType(typ.Value) {
+			return false
+		}
+		if !isKeyType(typ.Key) {
+			c.errorf(typ.Key, "%w: %s", ErrInvalidKey, typeString(typ.Key))
+			return false
+		}
+	}
+	if typ.IsArray && typ.ArrayLen < 0 {
+		c.errorf(typ, "%w: %s", ErrInvalidArrayLen, typeString(typ))
+		return false
+	}
+	return true
+}
+
+func (c *checker) declare(node ast.Node, name string, typ *ast.Type) {
+	if _, ok := c.env.vars[name]; ok {
+		c.errorf(node, "%w: %s", ErrVarExists, name)
+		return
+	}
+	c.env.vars[name] = typ
+}
+
+func (c *checker) openScope() {
+	c.env = &env{vars: make(map[string]*ast.Type), parent: c.env}
+}
+
+func (c *checker) closeScope() {
+	c.env = c.env.parent
+}
+
+func (c *checker) stmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		c.stmt(stmt)
+	}
+}
+
+func (c *checker) block(stmts []ast.Stmt) {
+	c.openScope()
+	c.stmts(stmts)
+	c.closeScope()
+}
+
+func (c *checker) stmt(stmt ast.Stmt) {
+	switch v := stmt.(type) {
+	case *ast.VarStmt:
+		if !c.validType(v.Type) {
+			return
+		}
+		if v.Val != nil {
+			c.assignable(v.Val, c.expr(v.Val), v.Type)
+		}
+		c.declare(v.Name, v.Name.Value, v.Type)
+		if v.Const {
+			if c.env.consts == nil {
+				c.env.consts = make(map[string]ast.Expr)
+			}
+			c.env.consts[v.Name.Value] = v.Val
+		}
+	case *ast.AssignStmt:
+		want := c.target(v.Var)
+		got := c.expr(v.Val)
+		if want == nil || got == nil {
+			return
+		}
+		if v.Op != token.ILLEGAL && (!binaryOps[v.Op][want.Kind] || want.IsArray) {
+			c.errorf(v, "%w: %s %s= %s", ErrOpNotSupported, typeString(want), v.Op, typeString(got))
+			return
+		}
+		c.assignable(v.Val, got, want)
+	case *ast.IncDecStmt:
+		typ := c.target(v.Var)
+		if typ != nil && !isKind(typ, ast.TInt) && !isKind(typ, ast.TFloat) {
+			c.errorf(v, "%w: %s%s", ErrOpNotSupported, typeString(typ), v.Op)
+		}
+	case *ast.CallStmt:
+		c.call(v.CallExpr)
+	case *ast.IfStmt:
+		c.cond(v.Cond)
+		c.block(v.Then)
+		switch elseBlock := v.Else.(type) {
+		case nil:
+		case ast.Stmts:
+			c.block(elseBlock)
+		default:
+			c.stmt(elseBlock)
+		}
+	case *ast.ForStmt:
+		c.openScope()
+		if v.Init != nil {
+			c.stmt(v.Init)
+		}
+		if v.Cond != nil {
+			c.cond(v.Cond)
+		}
+		if v.Post != nil {
+			c.stmt(v.Post)
+		}
+		c.loops++
+		c.block(v.Body)
+		c.loops--
+		c.closeScope()
+	case *ast.ForEachStmt:
+		c.openScope()
+		key, value := c.iterated(v)
+		if v.Key != nil && key != nil {
+			c.declare(v.Key, v.Key.Value, key)
+		}
+		if value != nil {
+			c.declare(v.Value, v.Value.Value, value)
+		}
+		c.loops++
+		c.block(v.Body)
+		c.loops--
+		c.closeScope()
+	case *ast.ReturnStmt:
+		want := c.fn.ReturnType
+		switch {
+		case v.Value == nil && !isVoid(want):
+			c.errorf(v, "%w: %s", ErrMissingReturnValue, typeString(want))
+		case v.Value != nil && isVoid(want):
+			c.value(v.Value)
+			c.errorf(v.Value, "%w", ErrReturnValueInVoid)
+		case v.Value != nil:
+			if got := c.value(v.Value); got != nil && !fits(v.Value, got, want) {
+				c.errorf(v.Value, "%w: %s керек, бірақ %s берілген", ErrReturnType, typeString(want), typeString(got))
+			}
+		}
+	case *ast.SwitchStmt:
+		c.switchStmt(v)
+	case *ast.LabeledStmt:
+		if slices.Contains(c.labels, v.Label.Value) {
+			c.errorf(v.Label, "%w: %s", ErrDuplicateLabel, v.Label.Value)
+		}
+		c.labels = append(c.labels, v.Label.Value)
+		c.stmt(v.Stmt)
+		c.labels = c.labels[:len(c.labels)-1]
+	case *ast.BreakStmt:
+		switch {
+		case v.Label != nil:
+			c.label(v.Label)
+		case c.loops == 0 && c.switches == 0:
+			c.errorf(v, "%w", ErrBreakOutsideLoop)
+		}
+	case *ast.ContinueStmt:
+		switch {
+		case v.Label != nil:
+			c.label(v.Label)
+		case c.loops == 0:
+			c.errorf(v, "%w", ErrContinueOutsideLoop)
+		}
+	case ast.Stmts:
+		c.block(v)
+	}
+}
+
+// label checks that the label of a тоқта or an өткіз names a loop around it.
+func (c *checker) label(label *ast.NameExpr) {
+	if !slices.Contains(c.labels, label.Value) {
+		c.errorf(label, "%w: %s", ErrUnknownLabel, label.Value)
+	}
+}
+
+func (c *checker) switchStmt(stmt *ast.SwitchStmt) {
+	tag := c.value(stmt.Tag)
+	if tag != nil && !isKeyType(tag) {
+		c.errorf(stmt.Tag, "%w: %s", ErrSwitchType, typeString(tag))
+		tag = nil
+	}
+	seen := make(map[string]bool)
+	hasDefault := false
+	for _, clause := range stmt.Cases {
+		if clause.Values == nil {
+			if hasDefault {
+				c.errorf(clause, "%w", ErrDuplicateDefault)
+			}
+			hasDefault = true
+		}
+		for _, val := range clause.Values {
+			got := c.value(val)
+			if got != nil && tag != nil && !identical(got, tag) {
+				c.errorf(val, "%w: %s керек, бірақ %s берілген", ErrNotSameType, typeString(tag), typeString(got))
+			}
+			key := c.caseKey(val)
+			if key != "" && seen[key] {
+				c.errorf(val, "%w", ErrDuplicateCase)
+			}
+			seen[key] = true
+		}
+		c.switches++
+		c.block(clause.Body)
+		c.switches--
+	}
+}
+
+// caseKey returns the key a case value is compared by to find duplicates:
+// the text of a literal or of the literal a constant is declared with, the
+// name of another constant, or "" for other values.
+func (c *checker) caseKey(val ast.Expr) string {
+	name, ok := val.(*ast.NameExpr)
+	if !ok || !c.env.isConst(name.Value) {
+		return literalKey(val)
+	}
+	if key := literalKey(c.env.owner(name.Value).consts[name.Value]); key != "" {
+		return key
+	}
+	return name.Value
+}
+
+// iterated returns the types of the key and the value a for-each loop
+// gives, both nil if its X can't be iterated.
+func (c *checker) iterated(loop *ast.ForEachStmt) (key, value *ast.Type) {
+	if rng, ok := loop.X.(*ast.RangeExpr); ok {
+		for _, bound := range []ast.Expr{rng.From, rng.To} {
+			if typ := c.value(bound); typ != nil && !isKind(typ, ast.TInt) {
+				c.errorf(bound, "%w: %s", ErrRangeNotInt, typeString(typ))
+			}
+		}
+		if loop.Key != nil {
+			c.errorf(loop.Key, "%w", ErrRangeKey)
+		}
+		return nil, primitive(ast.TInt)
+	}
+	typ := c.value(loop.X)
+	switch {
+	case typ == nil:
+		return nil, nil
+	case typ.IsArray:
+		return primitive(ast.TInt), elemType(typ)
+	case typ.Kind == ast.TMap && loop.Key == nil:
+		return nil, typ.Key
+	case typ.Kind == ast.TMap:
+		return typ.Key, typ.Value
+	case isKind(typ, ast.TString):
+		return primitive(ast.TInt), primitive(ast.TString)
+	}
+	c.errorf(loop.X, "%w: %s", ErrNotIterable, typeString(typ))
+	return nil, nil
+}
+
+// target checks the left side of an assignment and returns its type.
+func (c *checker) target(expr ast.Expr) *ast.Type {
+	if name := ast.AssignedVar(expr); name != nil && c.env.isConst(name.Value) {
+		c.errorf(expr, "%w: %s", ErrAssignToConst, name.Value)
+	}
+	switch ast.Unparen(expr).(type) {
+	case *ast.NameExpr, *ast.ArrayAccessExpr, *ast.SelectorExpr:
+		return c.expr(expr)
+	}
+	c.errorf(expr, "%w", ErrInvalidAssign)
+	return nil
+}
+
+func (c *checker) cond(expr ast.Expr) {
+	typ := c.expr(expr)
+	if typ != nil && !isKind(typ, ast.TBool) {
+		c.errorf(expr, "%w: %s", ErrCondNotBool, typeString(typ))
+	}
+}
+
+// assignable reports an error if a value of type got, computed by expr,
+// cannot be stored in a place of type want.
+func (c *checker) assignable(expr ast.Expr, got, want *ast.Type) {
+	if got == nil || want == nil || fits(expr, got, want) {
+		return
+	}
+	c.errorf(expr, "%w: %s керек, бірақ %s берілген", ErrNotSameType, typeString(want), typeString(got))
+}
+
+// fits reports whether a value of type got, computed by expr, has the type
+// want. A literal takes the type it is stored in: an array literal of any
+// length can be a list, and so can the values of a map literal.
+func fits(expr ast.Expr, got, want *ast.Type) bool {
+	if identical(got, want) {
+		return true
+	}
+	switch v := expr.(type) {
+	case *ast.ArrayExpr:
+		return want.IsList() && got.IsArray && identical(elemType(got), elemType(want))
+	case *ast.MapExpr:
+		if !isKind(got, ast.TMap) || !isKind(want, ast.TMap) || !identical(got.Key, want.Key) {
+			return false
+		}
+		for _, val := range v.Values {
+			if !fits(val, got.Value, want.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// value checks expr and reports an error if it does not produce a value.
+func (c *checker) value(expr ast.Expr) *ast.Type {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		typ := c.call(call)
+		if typ != nil && isVoid(typ) {
+			c.errorf(expr, "%w", ErrVoidValue)
+			return nil
+		}
+		return typ
+	}
+	return c.expr(expr)
+}
+
+// expr returns the type of expr, or nil if it is invalid. The errors are
+// reported once, where they are found, a nil type does not report again.
+func (c *checker) expr(expr ast.Expr) *ast.Type {
+	switch v := expr.(type) {
+	case *ast.IntExpr:
+		return primitive(ast.TInt)
+	case *ast.FloatExpr:
+		return primitive(ast.TFloat)
+	case *ast.StringExpr:
+		return primitive(ast.TString)
+	case *ast.InterpExpr:
+		for _, part := range v.Parts {
+			c.value(part)
+		}
+		return primitive(ast.TString)
+	case *ast.BoolExpr:
+		return primitive(ast.TBool)
+	case *ast.NameExpr:
+		typ := c.env.lookup(v.Value)
+		if typ == nil {
+			c.errorf(v, "%w: %s", ErrUndefinedName, v.Value)
+		}
+		return typ
+	case *ast.ArrayExpr:
+		var elem *ast.Type
+		for _, el := range v.Elements {
+			typ := c.value(el)
+			if typ == nil {
+				return nil
+			}
+			if typ.IsArray {
+				c.errorf(el, "%w", ErrNestedArray)
+				return nil
+			}
+			if elem == nil {
+				elem = typ
+			} else if !identical(elem, typ) {
+				c.errorf(el, "%w: %s керек, бірақ %s берілген", ErrNotSameType, typeString(elem), typeString(typ))
+				return nil
+			}
+		}
+		if elem == nil {
+			c.errorf(v, "%w", ErrEmptyArray)
+			return nil
+		}
+		arr := *elem
+		arr.IsArray, arr.ArrayLen = true, len(v.Elements)
+		return &arr
+	case *ast.MapExpr:
+		typ := primitive(ast.TMap)
+		arrayLits := !slices.ContainsFunc(v.Values, func(val ast.Expr) bool {
+			_, ok := val.(*ast.ArrayExpr)
+			return !ok
+		})
+		seen := make(map[string]bool)
+		for i := range v.Keys {
+			key, val := c.value(v.Keys[i]), c.value(v.Values[i])
+			if key == nil || val == nil {
+				return nil
+			}
+			if typ.Key == nil {
+				typ.Key, typ.Value = key, val
+				if !isKeyType(key) {
+					c.errorf(v.Keys[i], "%w: %s", ErrInvalidKey, typeString(key))
+					return nil
+				}
+			}
+			if !identical(key, typ.Key) {
+				c.errorf(v.Keys[i], "%w: %s керек, бірақ %s берілген", ErrNotSameType, typeString(typ.Key), typeString(key))
+				return nil
+			}
+			switch {
+			case fits(v.Values[i], val, typ.Value):
+			case arrayLits && identical(elemType(val), elemType(typ.Value)):
+				// array literals of different lengths make a map of lists
+				typ.Value = elemType(val)
+				typ.Value.IsArray = true
+			default:
+				c.errorf(v.Values[i], "%w: %s керек, бірақ %s берілген", ErrNotSameType, typeString(typ.Value), typeString(val))
+				return nil
+			}
+			// the same literal twice is a mistake, the first value is lost
+			if lit := literalKey(v.Keys[i]); lit != "" {
+				if seen[lit] {
+					c.errorf(v.Keys[i], "%w: %s", ErrDuplicateKey, lit)
+				}
+				seen[lit] = true
+			}
+		}
+		return typ
+	case *ast.ArrayAccessExpr:
+		arr := c.value(v.Array)
+		index := c.value(v.Index)
+		if arr == nil || index == nil {
+			return nil
+		}
+		if isKind(arr, ast.TMap) {
+			c.assignable(v.Index, index, arr.Key)
+			return arr.Value
+		}
+		if !arr.IsArray {
+			c.errorf(v.Array, "%w: %s", ErrIndexNotArray, typeString(arr))
+			return nil
+		}
+		if !isKind(index, ast.TInt) {
+			c.errorf(v.Index, "%w: %s", ErrIndexNotInt, typeString(index))
+		}
+		if i, ok := v.Index.(*ast.IntExpr); ok && !arr.IsList() && (i.Value < 0 || i.Value >= arr.ArrayLen) {
+			c.errorf(v.Index, "%w: %d, ұзындығы %d", ErrOutOfBound, i.Value, arr.ArrayLen)
+		}
+		return elemType(arr)
+	case *ast.SelectorExpr:
+		typ := c.value(v.Struct)
+		if typ == nil {
+			return nil
+		}
+		if typ.IsArray || typ.Kind != ast.TStruct {
+			c.errorf(v.Struct, "%w: %s", ErrSelectorNotStruct, typeString(typ))
+			return nil
+		}
+		decl, ok := c.structs[typ.Name.Value]
+		if !ok {
+			return nil // validType has reported the unknown type
+		}
+		for _, field := range decl.Fields {
+			if field.Name == v.Field.Value {
+				return field.Type
+			}
+		}
+		c.errorf(v.Field, "%w: %s.%s", ErrNoSuchField, typ.Name.Value, v.Field.Value)
+		return nil
+	case *ast.CallExpr:
+		return c.value(v)
+	case *ast.ParenExpr:
+		return c.value(v.X)
+	case *ast.UnaryOpExpr:
+		typ := c.value(v.Operand)
+		if typ == nil {
+			return nil
+		}
+		if !unaryOps[v.Op][typ.Kind] || typ.IsArray {
+			c.errorf(v, "%w: %s%s", ErrOpNotSupported, v.Op, typeString(typ))
+			return nil
+		}
+		return typ
+	case *ast.OpExpr:
+		left, right := c.value(v.Left), c.value(v.Right)
+		if left == nil || right == nil {
+			return nil
+		}
+		if !identical(left, right) {
+			c.errorf(v, "%w: %s %s %s", ErrOpTypes, typeString(left), v.Op, typeString(right))
+			return nil
+		}
+		if !binaryOps[v.Op][left.Kind] || left.IsArray {
+			c.errorf(v, "%w: %s %s %s", ErrOpNotSupported, typeString(left), v.Op, typeString(right))
+			return nil
+		}
+		switch v.Op {
+		case token.EQL, token.NEQ, token.LSS, token.GTR, token.LEQ, token.GEQ:
+			return primitive(ast.TBool)
+		}
+		return left
+	default:
+		c.errorf(expr, "%w", ErrUnknownExpr)
+		return nil
+	}
+}
+
+// call checks a function call and returns the declared return type.
+func (c *checker) call(call *ast.CallExpr) *ast.Type {
+	name, ok := call.Func.(*ast.NameExpr)
+	if !ok {
+		c.errorf(call.Func, "%w", ErrNotFunc)
+		return nil
+	}
+	decl, ok := c.funcs[name.Value]
+	if !ok {
+		args := make([]*ast.Type, len(call.Args))
+		for i, arg := range call.Args {
+			args[i] = c.value(arg)
+		}
+		builtin, ok := builtinFuncs[name.Value]
+		if !ok {
+			c.errorf(name, "%w: %s", ErrUndefinedFunc, name.Value)
+			return nil
+		}
+		if slices.Contains(args, nil) {
+			return nil
+		}
+		return builtin(c, call, args)
+	}
+	if len(call.Args) != len(decl.Args) {
+		c.errorf(call, "%w: %s функциясы %d аргумент алады, бірақ %d берілген", ErrArgCount, name.Value, len(decl.Args), len(call.Args))
+	}
+	for i, arg := range call.Args {
+		typ := c.value(arg)
+		if i < len(decl.Args) {
+			c.assignable(arg, typ, decl.Args[i].Type)
+		}
+	}
+	return decl.ReturnType
+}
+
+var unaryOps = map[token.Token]map[ast.Kind]bool{
+	token.SUB: {ast.TInt: true, ast.TFloat: true},
+	token.NOT: {ast.TBool: true},
+}
+
+var binaryOps = map[token.Token]map[ast.Kind]bool{
+	token.MUL:  {ast.TInt: true, ast.TFloat: true},
+	token.DIV:  {ast.TInt: true, ast.TFloat: true},
+	token.MOD:  {ast.TInt: true},
+	token.ADD:  {ast.TInt: true, ast.TFloat: true, ast.TString: true},
+	token.SUB:  {ast.TInt: true, ast.TFloat: true},
+	token.EQL:  {ast.TInt: true, ast.TFloat: true, ast.TString: true, ast.TBool: true},
+	token.NEQ:  {ast.TInt: true, ast.TFloat: true, ast.TString: true, ast.TBool: true},
+	token.LSS:  {ast.TInt: true, ast.TFloat: true, ast.TString: true},
+	token.GTR:  {ast.TInt: true, ast.TFloat: true, ast.TString: true},
+	token.LEQ:  {ast.TInt: true, ast.TFloat: true, ast.TString: true},
+	token.GEQ:  {ast.TInt: true, ast.TFloat: true, ast.TString: true},
+	token.LAND: {ast.TBool: true},
+	token.LOR:  {ast.TBool: true},
+}
+
+func primitive(kind ast.Kind) *ast.Type {
+	return &ast.Type{Kind: kind, Name: &ast.NameExpr{Value: kind.String()}}
+}
+
+// isKeyType reports whether typ can be the type of the keys of a map.
+func isKeyType(typ *ast.Type) bool {
+	return isKind(typ, ast.TInt) || isKind(typ, ast.TString) || isKind(typ, ast.TBool)
+}
+
+// literalKey returns the text of a literal map key, or "" for other keys.
+func literalKey(key ast.Expr) string {
+	switch v := key.(type) {
+	case *ast.StringExpr:
+		return strconv.Quote(v.Value)
+	case *ast.IntExpr:
+		return strconv.Itoa(v.Value)
+	case *ast.BoolExpr:
+		return strconv.FormatBool(v.Value)
+	case *ast.UnaryOpExpr:
+		if n, ok := v.Operand.(*ast.IntExpr); ok && v.Op == token.SUB {
+			return strconv.Itoa(-n.Value)
+		}
+	}
+	return ""
+}
+
+// elemType returns the type of the elements of the array or list arr.
+func elemType(arr *ast.Type) *ast.Type {
+	elem := *arr
+	elem.IsArray, elem.ArrayLen = false, 0
+	return &elem
+}
+
+func isVoid(typ *ast.Type) bool {
+	return isKind(typ, ast.TVoid)
+}
+
+func isKind(typ *ast.Type, kind ast.Kind) bool {
+	return !typ.IsArray && typ.Kind == kind
+}
+
+func identical(x, y *ast.Type) bool {
+	if x.Kind != y.Kind || x.IsArray != y.IsArray || x.ArrayLen != y.ArrayLen {
+		return false
+	}
+	if x.Kind == ast.TStruct {
+		return x.Name.Value == y.Name.Value
+	}
+	if x.Kind == ast.TMap {
+		return identical(x.Key, y.Key) && identical(x.Value, y.Value)
+	}
+	return true
+}
+
+func typeString(typ *ast.Type) string {
+	name := typ.Kind.String()
+	if typ.Kind == ast.TStruct {
+		name = typ.Name.Value
+	}
+	if typ.Kind == ast.TMap {
+		name = fmt.Sprintf("%s[%s]%s", name, typeString(typ.Key), typeString(typ.Value))
+	}
+	if typ.IsList() {
+		return "[]" + name
+	}
+	if typ.IsArray {
+		return fmt.Sprintf("[%d]%s", typ.ArrayLen, name)
+	}
+	return name
+}
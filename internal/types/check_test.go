@@ -0,0 +1,394 @@
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/testutils"
+	"github.com/nurtai325/qurtc/internal/types"
+)
+
+func TestCheckExamples(t *testing.T) {
+	testutils.RunOnExamples(func(name string, contents []byte) {
+		decls, err := parser.New(name, contents).Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := types.Check(contents, decls); err != nil {
+			t.Errorf("expected %s to type check, got:\n%v", name, err)
+		}
+	})
+}
+
+var checkTests = []struct {
+	name string
+	src  string
+	errs []error
+}{
+	{
+		name: "error in a branch that never runs",
+		src: `функция ештеңе негізгі() {
+	егер (жоқ) {
+		айнымалы а бүтін = "мәтін";
+	}
+}`,
+		errs: []error{types.ErrNotSameType},
+	},
+	{
+		name: "undefined names",
+		src: `функция ештеңе негізгі() {
+	а = 1;
+	жоқФункция(2);
+	егер (иә) {
+		айнымалы б бүтін = 1;
+	}
+	жаз(б);
+}`,
+		errs: []error{types.ErrUndefinedName, types.ErrUndefinedFunc, types.ErrUndefinedName},
+	},
+	{
+		name: "calls checked against the signature",
+		src: `функция бүтін қос(а бүтін, б бүтін) {
+	қайтар а + б;
+}
+
+функция ештеңе бос() {
+}
+
+функция ештеңе негізгі() {
+	айнымалы а бүтін = қос(1);
+	айнымалы б бүтін = қос(1, "2");
+	айнымалы в бүтін = бос();
+}`,
+		errs: []error{types.ErrArgCount, types.ErrNotSameType, types.ErrVoidValue},
+	},
+	{
+		name: "operators and conditions",
+		src: `функция ештеңе негізгі() {
+	айнымалы а бүтін = 1 + 2.5;
+	айнымалы б шын = !1;
+	айнымалы в жол = "а" - "б";
+	егер (1) {
+	}
+	қайтала (айнымалы и бүтін = 0; и; и = и + 1) {
+	}
+}`,
+		errs: []error{types.ErrOpTypes, types.ErrOpNotSupported, types.ErrOpNotSupported, types.ErrCondNotBool, types.ErrCondNotBool},
+	},
+	{
+		name: "arrays and structs",
+		src: `құрылым нүкте {
+	х бүтін,
+	у бүтін,
+}
+
+функция ештеңе негізгі() {
+	айнымалы т [3]бүтін = {1, 2};
+	айнымалы н нүкте;
+	н.з = 1;
+	т[3] = 1;
+	т["0"] = 1;
+	н[0] = 1;
+	айнымалы с бүтін = т.х;
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrNoSuchField, types.ErrOutOfBound, types.ErrIndexNotInt, types.ErrIndexNotArray, types.ErrSelectorNotStruct},
+	},
+	{
+		name: "declarations",
+		src: `құрылым а {
+	б а,
+}
+
+құрылым в {
+	г жоқТип,
+}
+
+функция ештеңе негізгі() {
+	айнымалы х бүтін;
+	айнымалы х жол;
+	тоқта;
+}
+
+функция ештеңе негізгі() {
+}`,
+		errs: []error{types.ErrRecursiveStruct, types.ErrUnknownType, types.ErrVarExists, types.ErrBreakOutsideLoop, types.ErrDuplicateFunc},
+	},
+	{
+		name: "fields of unknown struct types",
+		src: `құрылым а {
+	б жоқТип,
+}
+
+функция жоқТип ф() {
+}
+
+функция ештеңе негізгі() {
+	жаз(ф().х);
+	айнымалы х а;
+	жаз(х.б.в);
+}`,
+		errs: []error{types.ErrUnknownType, types.ErrUnknownType, types.ErrMissingReturn},
+	},
+	{
+		name: "return values",
+		src: `функция бүтін бір() {
+	қайтар "бір";
+}
+
+функция ештеңе бос() {
+	егер (иә) {
+		қайтар;
+	}
+	қайтар 1;
+}
+
+функция бүтін екі() {
+	қайтар;
+}
+
+функция бүтін үш(а бүтін) {
+	егер (а > 0) {
+		қайтар 3;
+	} әйтпесе егер (а < 0) {
+		қайтар -3;
+	}
+}
+
+функция бүтін төрт(а бүтін) {
+	егер (а > 0) {
+		қайтар 4;
+	} әйтпесе {
+		қайтар -4;
+	}
+}
+
+функция []бүтін тізім() {
+	қайтар {1, 2, 3};
+}
+
+функция [2]бүтін жұп() {
+	қайтар {1, 2, 3};
+}
+
+функция ештеңе негізгі() {
+}`,
+		errs: []error{types.ErrReturnType, types.ErrReturnValueInVoid, types.ErrMissingReturnValue, types.ErrMissingReturn, types.ErrReturnType},
+	},
+	{
+		name: "interpolated strings",
+		src: `функция ештеңе бос() {
+}
+
+функция ештеңе негізгі() {
+	айнымалы а бүтін = "{1 + 2}";
+	жаз("{бос()} {белгісіз} {1 + "1"}");
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrVoidValue, types.ErrUndefinedName, types.ErrOpTypes},
+	},
+	{
+		name: "globals and constants",
+		src: `айнымалы а бүтін = б;
+тұрақты б бүтін = 1;
+тұрақты в [2]бүтін = {1, 2};
+айнымалы г жол = "г";
+
+функция ештеңе негізгі() {
+	б = 2;
+	в[0] = 3;
+	г = "д";
+	тұрақты д бүтін = 4;
+	д = 5;
+}`,
+		errs: []error{types.ErrUndefinedName, types.ErrAssignToConst, types.ErrAssignToConst, types.ErrAssignToConst},
+	},
+	{
+		name: "parenthesised constants",
+		src: `тұрақты к []бүтін = {1, 2, 3};
+тұрақты с сөздік[жол]бүтін = {"а": 1};
+
+функция ештеңе негізгі() {
+	(к)[0] = 99;
+	((к))[1] += 1;
+	қос((к), 1);
+	өшір((к), 0);
+	өшір((с), "а");
+	жаз((к)[0]);
+}`,
+		errs: []error{types.ErrAssignToConst, types.ErrAssignToConst, types.ErrAssignToConst, types.ErrAssignToConst, types.ErrAssignToConst},
+	},
+	{
+		name: "compound assignments and increments",
+		src: `тұрақты т бүтін = 1;
+
+функция ештеңе негізгі() {
+	айнымалы а бүтін = 1;
+	айнымалы ж жол = "а";
+	а += 2.5;
+	ж -= "б";
+	ж++;
+	т--;
+	(а) = 2;
+	(а)++;
+	(а) = "2";
+	а = (ж);
+	(1) = 2;
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrOpNotSupported, types.ErrOpNotSupported, types.ErrAssignToConst, types.ErrNotSameType, types.ErrNotSameType, types.ErrInvalidAssign},
+	},
+	{
+		name: "lists",
+		src: `тұрақты т []бүтін = {1, 2};
+
+функция ештеңе негізгі() {
+	айнымалы а []бүтін = {1, 2, 3};
+	айнымалы б [3]бүтін = {1, 2, 3};
+	айнымалы в []бүтін = б;
+	қос(а, 4, "5");
+	қос(б, 4);
+	қос(т, 3);
+	өшір(а, 1.5);
+	айнымалы г [2]бүтін = бөлік(а, 0, 2);
+	айнымалы д бүтін = ұзындық(а) + ұзындық("сәлем") + ұзындық(1);
+	а[10] = 1;
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrNotSameType, types.ErrNotList, types.ErrAssignToConst, types.ErrIndexNotInt, types.ErrNotSameType, types.ErrLenArg},
+	},
+	{
+		name: "loops",
+		src: `функция ештеңе негізгі() {
+	айнымалы т []бүтін = {1, 2};
+	айнымалы с сөздік[жол]бөлшек = {"а": 1.5};
+	қайтала(і, х : т) {
+		х = і + х;
+	}
+	қайтала(к, м : с) {
+		к = м;
+	}
+	қайтала(к : с) {
+		к += "!";
+	}
+	қайтала(ә : "сәлем") {
+		ә = 1;
+	}
+	қайтала(і : 0..2.5) {
+	}
+	қайтала(і, х : 0..10) {
+	}
+	қайтала(х : 10) {
+	}
+	қайтала(1) {
+		тоқта;
+	}
+	қайтала {
+		өткіз;
+	}
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrNotSameType, types.ErrRangeNotInt, types.ErrRangeKey, types.ErrNotIterable, types.ErrCondNotBool},
+	},
+	{
+		name: "switch",
+		src: `тұрақты бір бүтін = 1;
+
+функция жол атауы(с бүтін) {
+	таңда(с) {
+	жағдай бір:
+		қайтар "бір";
+	әдепкі:
+		қайтар "көп";
+	}
+}
+
+функция ештеңе негізгі() {
+	таңда(атауы(1)) {
+	жағдай "бір", 1:
+		тоқта;
+	жағдай "көп", "бір":
+	әдепкі:
+	әдепкі:
+	}
+	таңда(1) {
+	жағдай бір, 1:
+	жағдай -1, 2, -1:
+	}
+	таңда(1.5) {
+	жағдай 1.5:
+	}
+}`,
+		errs: []error{types.ErrNotSameType, types.ErrDuplicateCase, types.ErrDuplicateDefault, types.ErrDuplicateCase, types.ErrDuplicateCase, types.ErrSwitchType},
+	},
+	{
+		name: "labels",
+		src: `функция бүтін бірінші() {
+	сыртқы: қайтала {
+		қайтала {
+			тоқта сыртқы;
+		}
+	}
+}
+
+функция бүтін екінші() {
+	сыртқы: қайтала {
+		қайтала {
+			тоқта;
+		}
+		таңда(1) {
+		жағдай 1:
+			тоқта;
+		}
+	}
+}
+
+функция ештеңе негізгі() {
+	а: қайтала(і : 0..3) {
+		а: қайтала {
+			өткіз а;
+		}
+		тоқта б;
+	}
+	өткіз а;
+}`,
+		errs: []error{types.ErrMissingReturn, types.ErrDuplicateLabel, types.ErrUnknownLabel, types.ErrUnknownLabel},
+	},
+	{
+		name: "maps",
+		src: `тұрақты т сөздік[бүтін]жол = {1: "бір"};
+
+функция ештеңе негізгі() {
+	айнымалы а сөздік[жол]бүтін = {"а": 1, "б": 2, "а": 3};
+	айнымалы б сөздік[жол][]бүтін = {"а": {1}, "б": {1, 2}};
+	айнымалы в сөздік[бөлшек]бүтін;
+	айнымалы г сөздік[жол]бүтін = {"а": "б"};
+	а[1] = 2;
+	а["в"] = "г";
+	т[2] = "екі";
+	өшір(т, 1);
+	айнымалы д шын = бар(а, "а") && бар(б, 1) && бар(1, 1);
+}`,
+		errs: []error{types.ErrDuplicateKey, types.ErrInvalidKey, types.ErrNotSameType, types.ErrNotSameType, types.ErrNotSameType, types.ErrAssignToConst, types.ErrAssignToConst, types.ErrNotSameType, types.ErrNotMap},
+	},
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range checkTests {
+		t.Run(tt.name, func(t *testing.T) {
+			decls, err := parser.New("test.құрт", []byte(tt.src)).Parse()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = types.Check([]byte(tt.src), decls)
+			var errs types.ErrorList
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected types.ErrorList, got %v", err)
+			}
+			if len(errs) != len(tt.errs) {
+				t.Fatalf("expected %d errors, got %d:\n%v", len(tt.errs), len(errs), err)
+			}
+			for i, want := range tt.errs {
+				if !errors.Is(errs[i], want) {
+					t.Errorf("error %d: expected %v, got %v", i, want, errs[i].Err)
+				}
+			}
+		})
+	}
+}
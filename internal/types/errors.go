@@ -4,7 +4,53 @@ import "errors"
 
 var (
 	ErrOutOfBound  = errors.New("тізім ұзындығынан тең немесе одан асатын немесе теріс индекс берілген")
+	ErrSliceBounds = errors.New("бөліктің басы мен соңы 0 мен тізім ұзындығы аралығында болып, басы соңынан аспауы керек")
+	ErrNoKey       = errors.New("сөздікте бұндай кілт жоқ, оны бар функциясымен тексеруге болады")
 	ErrNotSameType = errors.New("айнымалыға мән бергенде немесе тізімді немесе құрылымды өзгерткенде өзгеретін мүше мен жаңа мәннің типтері бірдей болуы керек")
 	ErrNoSuchField = errors.New("бұндай мүше бұл құрылымда жоқ")
 	ErrUnknownType = errors.New("бұндай тип жоқ")
+
+	ErrDuplicateStruct     = errors.New("бұндай құрылым жарияланып қойған")
+	ErrDuplicateFunc       = errors.New("бұндай функция жарияланып қойған")
+	ErrDuplicateField      = errors.New("бұл атпен мүше құрылымда бар")
+	ErrRecursiveStruct     = errors.New("құрылым өзін-өзі мүше ретінде қамти алмайды")
+	ErrInvalidArrayLen     = errors.New("тізім ұзындығы 0-ден үлкен болуы керек")
+	ErrVarExists           = errors.New("бұл атпен айнымалы бар қайтадан жариялай алмайсыз")
+	ErrUndefinedName       = errors.New("бұл атпен айнымалы жоқ")
+	ErrUndefinedFunc       = errors.New("бұндай функция жоқ")
+	ErrNotFunc             = errors.New("тек функцияны атауы арқылы шақыруға болады")
+	ErrArgCount            = errors.New("функция шақырылғанда аргументтер саны дұрыс берілмеген")
+	ErrVoidValue           = errors.New("ештеңе типті мән болмайды, ештеңе қайтармайтын функцияның нәтижесін қолдануға болмайды")
+	ErrInvalidAssign       = errors.New("мән тек айнымалыға, тізім мүшесіне немесе құрылым мүшесіне берілуі мүмкін")
+	ErrAssignToConst       = errors.New("тұрақтының мәнін өзгертуге болмайды")
+	ErrCondNotBool         = errors.New("шарт тек шын типті мән бола алады")
+	ErrOpTypes             = errors.New("операция тек бірдей типтегі мәндерге қолданылады")
+	ErrOpNotSupported      = errors.New("бұл операция мына типке қолданылмайды")
+	ErrIndexNotArray       = errors.New("тізім мүшесін алу операциясы тек тізімдерге ғана болады")
+	ErrIndexNotInt         = errors.New("тізім индексі бүтін сан болуы керек")
+	ErrSelectorNotStruct   = errors.New("құрылым мүшесін алу операциясы тек құрылымдарға ғана болады")
+	ErrNestedArray         = errors.New("тізімнің мүшесі тізім бола алмайды")
+	ErrEmptyArray          = errors.New("бос тізім жазуға болмайды, айнымалыны мәнсіз жариялаңыз")
+	ErrInvalidKey          = errors.New("сөздік кілті тек бүтін, жол немесе шын бола алады")
+	ErrDuplicateKey        = errors.New("сөздікте бұл кілт екі рет жазылған")
+	ErrNotList             = errors.New("қос тек ұзындығы жазылмаған тізімге, мысалы []бүтін, қолданылады")
+	ErrRemoveArg           = errors.New("өшір тек ұзындығы жазылмаған тізімге немесе сөздікке қолданылады")
+	ErrNotMap              = errors.New("бар тек сөздікке қолданылады")
+	ErrLenArg              = errors.New("ұзындық тек тізімге, сөздікке немесе жолға қолданылады")
+	ErrSliceNotArray       = errors.New("бөлік тек тізімнен алынады")
+	ErrNotIterable         = errors.New("қайтала тек тізімді, жолды, сөздікті немесе бүтін сандар ауқымын, мысалы 0..10, аралай алады")
+	ErrRangeNotInt         = errors.New("ауқымның басы мен соңы бүтін сан болуы керек")
+	ErrRangeKey            = errors.New("ауқымды аралағанда тек бір айнымалы жазылады, мысалы қайтала (і : 0..10)")
+	ErrSwitchType          = errors.New("таңда тек бүтін, жол немесе шын мәнді таңдай алады")
+	ErrDuplicateCase       = errors.New("бұл мән таңданың басқа жағдайында жазылған")
+	ErrDuplicateDefault    = errors.New("таңдада тек бір әдепкі болады")
+	ErrUnknownLabel        = errors.New("бұл белгімен қайтала жоқ, белгі тоқта немесе өткіз тұрған қайталаның өзіне немесе сыртындағы қайталаға жазылуы керек")
+	ErrDuplicateLabel      = errors.New("бұл белгі сыртқы қайталаға жазылып қойған")
+	ErrBreakOutsideLoop    = errors.New("тоқта нұсқауын тек қайтала немесе таңда нұсқауының денесінде қолдануға болады")
+	ErrContinueOutsideLoop = errors.New("өткіз нұсқауын тек қайтала нұсқауының денесінде қолдануға болады")
+	ErrUnknownExpr         = errors.New("бұндай өрнек жоқ")
+	ErrReturnType          = errors.New("қайтарылған мәннің типі функцияның жарияланған типіне сай емес")
+	ErrReturnValueInVoid   = errors.New("ештеңе типті функция мән қайтара алмайды, тек 'қайтар;' жазуға болады")
+	ErrMissingReturnValue  = errors.New("'қайтар;' тек ештеңе типті функцияларда жазылады, бұл функция мән қайтаруы керек")
+	ErrMissingReturn       = errors.New("функция соңына дейін мән қайтармай жетуі мүмкін, соңында қайтар жазыңыз")
 )
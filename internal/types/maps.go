@@ -0,0 +1,56 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+)
+
+func NewMap() *Map {
+	return &Map{
+		values: make(map[Type]Type),
+	}
+}
+
+func (m *Map) Len() int {
+	return len(m.keys)
+}
+
+func (m *Map) Get(key Type) (Type, error) {
+	val, ok := m.values[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNoKey, key)
+	}
+	return val, nil
+}
+
+// Has reports whether the map has key.
+func (m *Map) Has(key Type) bool {
+	_, ok := m.values[key]
+	return ok
+}
+
+// Set sets the value of key, a new key is added after the others.
+func (m *Map) Set(key, val Type) error {
+	if len(m.keys) > 0 && (!IsSameType(m.keys[0], key) || !IsSameType(m.values[m.keys[0]], val)) {
+		return ErrNotSameType
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = val
+	return nil
+}
+
+// Delete removes key from the map, it does nothing if there is no key.
+func (m *Map) Delete(key Type) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	m.keys = slices.DeleteFunc(m.keys, func(k Type) bool { return k == key })
+}
+
+// Keys returns the keys in the order they were added.
+func (m *Map) Keys() []Type {
+	return slices.Clone(m.keys)
+}
@@ -29,6 +29,8 @@ func ZeroOf(typ *ast.Type, structTypes map[string]*ast.StructDecl) (Type, error)
 		return String(""), nil
 	case ast.TBool:
 		return Bool(false), nil
+	case ast.TMap:
+		return NewMap(), nil
 	case ast.TStruct:
 		structDecl, ok := structTypes[typ.Name.Value]
 		if !ok {
@@ -61,11 +63,23 @@ func IsOfType(val Type, typ *ast.Type) bool {
 	case *Array:
 		if !typ.IsArray {
 			return false
-		} else if typ.ArrayLen != v.Len() {
+		} else if !typ.IsList() && typ.ArrayLen != v.Len() {
 			return false
 		}
+		elem := *typ
+		elem.IsArray, elem.ArrayLen = false, 0
 		for _, el := range v.elements {
-			if !IsOfType(el, typ) {
+			if !IsOfType(el, &elem) {
+				return false
+			}
+		}
+		return true
+	case *Map:
+		if typ.IsArray || typ.Kind != ast.TMap {
+			return false
+		}
+		for key, val := range v.values {
+			if !IsOfType(key, typ.Key) || !IsOfType(val, typ.Value) {
 				return false
 			}
 		}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nurtai325/qurtc/internal/token"
 )
@@ -19,9 +20,17 @@ type (
 
 	Bool bool
 
+	// Array is the value of both arrays and lists, the checker makes sure
+	// only lists change their length.
 	Array struct {
 		elements []Type
-		length   int
+	}
+
+	// Map keeps its keys in the order they were added, so that a program
+	// prints and goes through a map the same way every time it runs.
+	Map struct {
+		keys   []Type
+		values map[Type]Type
 	}
 
 	Struct struct {
@@ -42,6 +51,19 @@ func (a *Array) String() string {
 	return fmt.Sprint(a.elements)
 }
 
+func (m *Map) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%v: %v", key, m.values[key])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
 func (Int) aType() {}
 
 func (Float) aType() {}
@@ -52,4 +74,6 @@ func (Bool) aType() {}
 
 func (*Array) aType() {}
 
+func (*Map) aType() {}
+
 func (*Struct) aType() {}
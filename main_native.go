@@ -3,20 +3,239 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/nurtai325/qurtc/internal/ast"
 	"github.com/nurtai325/qurtc/internal/exec"
+	"github.com/nurtai325/qurtc/internal/format"
+	"github.com/nurtai325/qurtc/internal/parser"
+	"github.com/nurtai325/qurtc/internal/scanner"
+	"github.com/nurtai325/qurtc/internal/source"
+	"github.com/nurtai325/qurtc/internal/token"
+	"github.com/nurtai325/qurtc/internal/translit"
 )
 
+// commands are run as `qurtc команда аргументтер`, `qurtc файл` runs the file.
+var commands = map[string]func(args []string) error{
+	"translit": translitCmd,
+	"tokens":   tokensCmd,
+	"ast":      astCmd,
+	"fmt":      fmtCmd,
+}
+
 func Main() error {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			return cmd(os.Args[2:])
+		}
+	}
 	if len(os.Args) != 2 {
 		return errors.New("аргумент ретінде код жазылған файл атын беріңіз")
 	}
 	filename := os.Args[1]
-	source, err := os.ReadFile(filename)
+	source, decls, err := readProgram(filename)
+	if err != nil {
+		return err
+	}
+	return exec.Run(os.Stdout, source, decls)
+}
+
+// readProgram reads and parses a program, a file ending with .json is read
+// as the program's AST written by `qurtc ast -json`.
+func readProgram(filename string) ([]byte, []ast.Decl, error) {
+	if strings.HasSuffix(filename, ".json") {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, nil, errors.New("берілген атпен файл табылмады")
+		}
+		decls, err := ast.DecodeJSON(filename, data)
+		return nil, decls, err
+	}
+	source, err := readFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	decls, err := parser.New(filename, source).Parse()
+	return source, decls, err
+}
+
+func readFile(filename string) ([]byte, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New("берілген атпен файл табылмады")
+	}
+	contents, err = source.Decode(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return contents, nil
+}
+
+func translitCmd(args []string) error {
+	flags := flag.NewFlagSet("translit", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "қолданылуы: qurtc translit [-to кирилл|латын|төте] [-w] файл...")
+		flags.PrintDefaults()
+	}
+	to := flags.String("to", token.Latin.String(), "кілт сөздер аударылатын жазу: кирилл, латын немесе төте")
+	write := flags.Bool("w", false, "нәтижені экранға шығармай, файлдың өзіне жазу")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	dialect, ok := token.ParseDialect(*to)
+	if !ok {
+		return fmt.Errorf("бұндай жазу жоқ: %s", *to)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("аударылатын файл берілмеген")
+	}
+	for _, filename := range flags.Args() {
+		source, err := readFile(filename)
+		if err != nil {
+			return err
+		}
+		out, err := translit.Translit(filename, source, dialect)
+		if err != nil {
+			return err
+		}
+		if *write {
+			if err := os.WriteFile(filename, out, 0o644); err != nil {
+				return err
+			}
+			continue
+		}
+		os.Stdout.Write(out)
+	}
+	return nil
+}
+
+func tokensCmd(args []string) error {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "қолданылуы: qurtc tokens [-json] файл")
+		flags.PrintDefaults()
+	}
+	asJSON := flags.Bool("json", false, "таңбаларды JSON түрінде шығару")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("бір файл атын беріңіз")
+	}
+	filename := flags.Arg(0)
+	source, err := readFile(filename)
 	if err != nil {
-		return errors.New("берілген атпен файл табылмады")
+		return err
+	}
+
+	type tokenJSON struct {
+		Token string    `json:"token"`
+		Lit   string    `json:"lit"`
+		Pos   token.Pos `json:"pos"`
+		End   token.Pos `json:"end"`
+		Err   string    `json:"error,omitempty"`
+	}
+	var toks []tokenJSON
+	s := scanner.New(filename, source, scanner.ScanComments)
+	for {
+		s.Scan()
+		tok := tokenJSON{Token: s.Tok().String(), Lit: s.Lit(), Pos: s.Pos(), End: s.End()}
+		if s.Err() != nil {
+			tok.Err = s.Err().Error()
+		}
+		if *asJSON {
+			toks = append(toks, tok)
+		} else {
+			fmt.Printf("%d:%d\t%s\t%q", tok.Pos.Line, tok.Pos.Col, tok.Token, tok.Lit)
+			if tok.Err != "" {
+				fmt.Printf("\t%s", tok.Err)
+			}
+			fmt.Println()
+		}
+		if s.Tok() == token.EOF {
+			break
+		}
+	}
+	if *asJSON {
+		out, err := json.MarshalIndent(toks, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	}
+	return nil
+}
+
+func astCmd(args []string) error {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "қолданылуы: qurtc ast [-json] файл")
+		flags.PrintDefaults()
+	}
+	asJSON := flags.Bool("json", false, "синтаксис ағашын JSON түрінде шығару, оны qurtc файл.json арқылы орындауға болады")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("бір файл атын беріңіз")
+	}
+	_, decls, err := readProgram(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return ast.EncodeJSON(os.Stdout, decls)
+	}
+	return ast.Fprint(os.Stdout, decls)
+}
+
+func fmtCmd(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "қолданылуы: qurtc fmt [-w] [-d] файл...")
+		flags.PrintDefaults()
+	}
+	write := flags.Bool("w", false, "нәтижені экранға шығармай, файлдың өзіне жазу")
+	diff := flags.Bool("d", false, "файлдың өзін емес, оған енгізілетін өзгерістерді көрсету")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("пішімделетін файл берілмеген")
+	}
+	for _, filename := range flags.Args() {
+		source, err := readFile(filename)
+		if err != nil {
+			return err
+		}
+		out, err := format.Source(filename, source)
+		if err != nil {
+			return err
+		}
+		if *diff {
+			fmt.Print(format.Diff(filename, source, out))
+		}
+		if *write {
+			if !bytes.Equal(source, out) {
+				if err := os.WriteFile(filename, out, 0o644); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !*diff {
+			os.Stdout.Write(out)
+		}
 	}
-	return exec.Exec(os.Stdout, filename, source)
+	return nil
 }
@@ -7,24 +7,41 @@ import (
 	"syscall/js"
 
 	"github.com/nurtai325/qurtc/internal/exec"
+	"github.com/nurtai325/qurtc/internal/format"
+	"github.com/nurtai325/qurtc/internal/source"
 )
 
 const (
-	execFnName = "qurtExec"
-	filename   = "негізгі.құрт"
+	execFnName   = "qurtExec"
+	formatFnName = "qurtFormat"
+	filename     = "негізгі.құрт"
 )
 
 func Main() error {
 	// TODO: try not to panic it stops the whole program in the browser
 	js.Global().Set(execFnName, js.FuncOf(func(this js.Value, args []js.Value) any {
 		stdout := strings.Builder{}
-		source := args[0].String()
-		err := exec.Exec(&stdout, filename, []byte(source))
+		// pasted code may still have CRLF line endings
+		code, err := source.Decode([]byte(args[0].String()))
+		if err == nil {
+			err = exec.Exec(&stdout, filename, code)
+		}
 		if err != nil {
 			stdout.WriteString(err.Error())
 			return stdout.String()
 		}
 		return stdout.String()
 	}))
+	// qurtFormat returns {code} with the formatted code or {error}
+	js.Global().Set(formatFnName, js.FuncOf(func(this js.Value, args []js.Value) any {
+		code, err := source.Decode([]byte(args[0].String()))
+		if err == nil {
+			code, err = format.Source(filename, code)
+		}
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return map[string]any{"code": string(code)}
+	}))
 	select{}
 }
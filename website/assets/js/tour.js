@@ -78,6 +78,16 @@ const lessons = [
 </ul>
 
 <p><code>әйтпесе</code> - шарт жалған болса орындалады.</p>
+
+<h2>Таңда командасы</h2>
+<p>Бір мәнді бірнеше мәнмен салыстыру үшін <code>таңда</code> жазылады. Мәні тең келген бірінші <code>жағдай</code> ғана орындалады, ешқайсысы тең келмесе <code>әдепкі</code> орындалады. Бір жағдайға бірнеше мәнді үтірмен бөліп жазуға болады:</p>
+
+<pre><code>таңда(күн) {
+жағдай 6, 7:
+    жаз("Демалыс");
+әдепкі:
+    жаз("Жұмыс күні");
+}</code></pre>
 `,
 		code: 'функция ештеңе негізгі() {\n    айнымалы жасы бүтін = 20;\n    \n    егер(жасы >= 18) {\n        жаз("Сіз ересексіз");\n    } әйтпесе {\n        жаз("Сіз кәмелетсізсіз");\n    }\n}'
 	},
@@ -96,9 +106,18 @@ const lessons = [
 <li>Қадамды анықтаймыз</li>
 </ul>
 
-<p>Мысалда санағыш 1-ден 5-ке дейін санайды.</p>
+<p>Мысалда санағыш 1-ден 5-ке дейін санайды. <code>i++</code> деген <code>i = i + 1</code> дегенмен бірдей, ал <code>қосынды += i</code> деген <code>қосынды = қосынды + i</code> дегенмен бірдей.</p>
+
+<h2>Тізімді аралау</h2>
+<p><code>қайтала(жеміс : жемістер)</code> тізімнің, жолдың немесе сөздіктің әр мүшесі үшін денені бір рет орындайды. <code>қайтала(і, жеміс : жемістер)</code> мүшемен бірге оның индексін де береді. <code>қайтала(і : 1..6)</code> і-ге 1-ден 5-ке дейінгі сандарды береді, соңғы сан кірмейді.</p>
+
+<h2>Шартпен қайталау</h2>
+<p><code>қайтала(шарт)</code> шарт иә болғанша қайталайды, ал <code>қайтала { }</code> <code>тоқта</code> орындалғанша тоқтамайды. <code>өткіз</code> келесі қадамға өтеді.</p>
+
+<h2>Белгілер</h2>
+<p>Циклдің алдына <code>сыртқы:</code> сияқты белгі жазсақ, ішкі циклдің ішінен <code>тоқта сыртқы;</code> сыртқы циклды тоқтатады, ал <code>өткіз сыртқы;</code> сыртқы циклдің келесі қадамына өтеді.</p>
 `,
-		code: 'функция ештеңе негізгі() {\n    жаз("Санақ басталды:");\n    \n    қайтала(айнымалы i бүтін = 1; i <= 5; i = i + 1) {\n        жаз(i);\n    }\n    \n    жаз("Санақ аяқталды!");\n}'
+		code: 'функция ештеңе негізгі() {\n    жаз("Санақ басталды:");\n    \n    қайтала(айнымалы i бүтін = 1; i <= 5; i++) {\n        жаз(i);\n    }\n    \n    айнымалы жемістер []жол = {"алма", "алмұрт", "өрік"};\n    қайтала(жеміс : жемістер) {\n        жаз(жеміс);\n    }\n    \n    жаз("Санақ аяқталды!");\n}'
 	},
 	{
 		title: 'Функциялар',
@@ -143,6 +162,7 @@ const totalLessons = lessons.length;
 const prevBtn = document.getElementById('prevBtn');
 const nextBtn = document.getElementById('nextBtn');
 const runBtn = document.getElementById('runBtn');
+const formatBtn = document.getElementById('formatBtn');
 const codeEditor = document.getElementById('codeEditor');
 const output = document.getElementById('output');
 const lessonContent = document.getElementById('lessonContent');
@@ -182,4 +202,12 @@ nextBtn.addEventListener('click', () => {
 runBtn.addEventListener('click', () => {
 	output.textContent = qurtExec(codeEditor.value);
 });
+formatBtn.addEventListener('click', () => {
+	const result = qurtFormat(codeEditor.value);
+	if (result.error) {
+		output.textContent = result.error;
+		return;
+	}
+	codeEditor.value = result.code;
+});
 updateLesson();